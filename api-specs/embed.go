@@ -0,0 +1,11 @@
+// Package apispecs bundles the schack.se OpenAPI spec fetched by
+// scripts/fetch-api-docs.sh, so the binary does not depend on the working
+// directory to find it.
+package apispecs
+
+import _ "embed"
+
+// SchackSE is the schack.se OpenAPI spec in schack-se-api-docs.json
+//
+//go:embed schack-se-api-docs.json
+var SchackSE []byte
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/msvens/mchess/internal/api"
+	"github.com/msvens/mchess/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Cache management commands",
+	Long:  `Commands for managing the mchess cache.`,
+}
+
+var cacheWarmCmd = &cobra.Command{
+	Use:   "warm",
+	Short: "Load configured targets into the cache",
+	Long: `Load the targets configured under warm in the config file into the cache:
+the players and rating lists of warm.clubs and of the clubs in warm.districts,
+and coming tournaments if warm.comingTournaments is set. Upstream requests
+are limited to warm.rateLimit per second.
+
+This is the same job mchess serve runs on warm.schedule. Exits with status 1
+if any item failed to load.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.Get()
+		cfg.SetupLogger()
+
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+
+		summary, err := api.WarmCache(ctx, cfg)
+		if err != nil {
+			slog.Error("Failed to warm cache", "error", err)
+			os.Exit(1)
+		}
+
+		failed := 0
+		for _, target := range warmTargets(summary.Loaded, summary.Failed) {
+			fmt.Printf("%-12s %6d loaded %6d failed\n", target, summary.Loaded[target], summary.Failed[target])
+			failed += summary.Failed[target]
+		}
+		if failed > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+var purgeGrace time.Duration
+
+var cachePurgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Delete expired cache entries",
+	Long: `Delete cache entries that expired more than cache.purgeGrace ago, in
+batches of cache.purgeBatchSize. Expired entries are kept until then so they
+can be served while upstream is down. Entries that never expire (historical
+ratings, finished tournaments) are not touched.
+
+This is the same job mchess serve runs every cache.purgeInterval.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.Get()
+		cfg.SetupLogger()
+
+		grace := cfg.Cache.PurgeGrace
+		if cmd.Flags().Changed("grace") {
+			grace = purgeGrace
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+
+		deleted, err := api.PurgeExpiredCache(ctx, cfg, grace)
+		for _, cacheType := range sortedKeys(deleted) {
+			fmt.Printf("%-12s %8d deleted\n", cacheType, deleted[cacheType])
+		}
+		if err != nil {
+			slog.Error("Failed to purge expired cache", "error", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// sortedKeys returns the keys of m, sorted
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// warmTargets returns the targets in the summary counts, sorted
+func warmTargets(counts ...map[string]int) []string {
+	seen := make(map[string]bool)
+	var targets []string
+	for _, m := range counts {
+		for target := range m {
+			if !seen[target] {
+				seen[target] = true
+				targets = append(targets, target)
+			}
+		}
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+func init() {
+	cachePurgeCmd.Flags().DurationVar(&purgeGrace, "grace", 0, "Delete entries that expired more than this long ago (default cache.purgeGrace)")
+	cacheCmd.AddCommand(cacheWarmCmd)
+	cacheCmd.AddCommand(cachePurgeCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
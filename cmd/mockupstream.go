@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/msvens/mchess/internal/config"
+	"github.com/msvens/mchess/internal/upstream"
+	"github.com/msvens/mchess/internal/upstream/fake"
+	"github.com/spf13/cobra"
+)
+
+var (
+	mockHost        string
+	mockPort        int
+	mockFixtureDir  string
+	mockCassetteDir string
+	mockLatency     time.Duration
+)
+
+var mockUpstreamCmd = &cobra.Command{
+	Use:   "mock-upstream",
+	Short: "Start a fake schack.se API",
+	Long: `Start a standalone fake of the schack.se API on a local port.
+
+Every endpoint in api-specs/schack-se-api-docs.json is served from the
+built-in fixtures, optionally extended with a fixture directory and with
+cassettes recorded in upstream record mode. Point upstream.baseUrl at the
+printed URL to run mchess without calling the federation API.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := config.Get()
+		cfg.SetupLogger()
+
+		fakeHandler := fake.NewHandler()
+		if mockFixtureDir != "" {
+			var err error
+			fakeHandler, err = fake.NewHandlerFS(os.DirFS(mockFixtureDir))
+			if err != nil {
+				slog.Error("Failed to load fixtures", "dir", mockFixtureDir, "error", err)
+				os.Exit(1)
+			}
+		}
+		fakeHandler.SetLatency(mockLatency)
+
+		var handler http.Handler = fakeHandler
+		if mockCassetteDir != "" {
+			handler = upstream.ServeCassettes(mockCassetteDir, fake.BasePath, fakeHandler)
+		}
+
+		addr := fmt.Sprintf("%s:%d", mockHost, mockPort)
+		httpServer := &http.Server{
+			Addr:    addr,
+			Handler: handler,
+		}
+
+		go func() {
+			slog.Info("Starting fake upstream", "addr", addr,
+				"baseUrl", fmt.Sprintf("http://%s%s", addr, fake.BasePath),
+				"fixtures", mockFixtureDir, "cassettes", mockCassetteDir)
+			if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				slog.Error("Fake upstream failed", "error", err)
+				os.Exit(1)
+			}
+		}()
+
+		quit := make(chan os.Signal, 1)
+		signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+		<-quit
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := httpServer.Shutdown(ctx); err != nil {
+			slog.Error("Fake upstream forced to shutdown", "error", err)
+		}
+		slog.Info("Fake upstream stopped")
+	},
+}
+
+func init() {
+	mockUpstreamCmd.Flags().StringVar(&mockHost, "host", "localhost", "host to listen on")
+	mockUpstreamCmd.Flags().IntVar(&mockPort, "port", 8081, "port to listen on")
+	mockUpstreamCmd.Flags().StringVar(&mockFixtureDir, "fixtures", "", "directory with extra fixtures, replacing built-in ones with the same path")
+	mockUpstreamCmd.Flags().StringVar(&mockCassetteDir, "cassettes", "", "directory with recorded cassettes, served before fixtures")
+	mockUpstreamCmd.Flags().DurationVar(&mockLatency, "latency", 0, "delay every response, e.g. 200ms")
+	rootCmd.AddCommand(mockUpstreamCmd)
+}
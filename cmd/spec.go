@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/msvens/mchess/internal/spec"
+	"github.com/spf13/cobra"
+)
+
+var specFile string
+
+var specCmd = &cobra.Command{
+	Use:   "spec",
+	Short: "Upstream API spec commands",
+	Long:  `Commands for working with the schack.se OpenAPI spec in api-specs/.`,
+}
+
+var specCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check models against the upstream API spec",
+	Long: `Compare the JSON fields and types of the model structs with the schemas
+in the schack.se OpenAPI spec. Run it after scripts/fetch-api-docs.sh to find
+fields that were added, renamed or removed upstream. The spec is bundled
+into the binary at build time, so rebuild or pass --spec to check a newly
+fetched one.
+
+Exits with status 1 if there are differences.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		s, err := spec.Load(specFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		name := specFile
+		if name == "" {
+			name = "the bundled spec"
+		}
+
+		issues := spec.Check(s, spec.Models)
+		if len(issues) == 0 {
+			fmt.Printf("%d models match %s\n", len(spec.Models), name)
+			return
+		}
+
+		for _, issue := range issues {
+			fmt.Println(issue)
+		}
+		fmt.Printf("\n%d differences between models and %s\n", len(issues), name)
+		os.Exit(1)
+	},
+}
+
+func init() {
+	specCheckCmd.Flags().StringVar(&specFile, "spec", "", "OpenAPI spec file (default: the bundled spec)")
+	specCmd.AddCommand(specCheckCmd)
+	rootCmd.AddCommand(specCmd)
+}
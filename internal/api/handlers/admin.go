@@ -0,0 +1,330 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/msvens/mchess/internal/model"
+	"github.com/msvens/mchess/internal/service"
+	"github.com/msvens/mchess/internal/upstream"
+)
+
+// AdminHandler handles mchess administration requests
+type AdminHandler struct {
+	stats   *service.StatsService
+	client  *upstream.Client
+	cache   *service.CacheAdminService
+	players *service.PlayerService // For refetching players
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(stats *service.StatsService, client *upstream.Client, cache *service.CacheAdminService, players *service.PlayerService) *AdminHandler {
+	return &AdminHandler{stats: stats, client: client, cache: cache, players: players}
+}
+
+// AdminAuth is middleware that requires token as a bearer token in the
+// Authorization header. If token is empty every request is rejected, so the
+// routes it guards are disabled until admin.token is set.
+func AdminAuth(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if token == "" {
+				writeError(w, http.StatusForbidden, "admin API is disabled, set admin.token to enable it")
+				return
+			}
+
+			bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) != 1 {
+				slog.Warn("Admin authentication failed", "method", r.Method, "path", r.URL.Path,
+					"remoteAddr", r.RemoteAddr, "requestId", middleware.GetReqID(r.Context()))
+				w.Header().Set("WWW-Authenticate", `Bearer realm="mchess admin"`)
+				writeError(w, http.StatusUnauthorized, "missing or invalid admin token")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// GetCacheStats returns hit/miss statistics per cache type
+// @Summary Get cache statistics
+// @Description Get cache hits, misses, upstream calls and hit ratio per cache type (mchess extension)
+// @Tags admin
+// @Produce json
+// @Security AdminToken
+// @Success 200 {object} model.CacheStatsResponse
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 403 {object} ErrorResponse "Admin API disabled"
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/cache/stats [get]
+func (h *AdminHandler) GetCacheStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.stats.GetStats(r.Context())
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, stats)
+}
+
+// GetUpstreamDrift returns the last observed differences between upstream
+// responses and our models
+// @Summary Get upstream schema drift
+// @Description Get fields upstream sent that our models lack, and model fields upstream no longer sends, per upstream endpoint. Requires upstream.driftDetection (mchess extension)
+// @Tags admin
+// @Produce json
+// @Security AdminToken
+// @Success 200 {object} model.UpstreamDriftResponse
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 403 {object} ErrorResponse "Admin API disabled"
+// @Router /admin/upstream/drift [get]
+func (h *AdminHandler) GetUpstreamDrift(w http.ResponseWriter, r *http.Request) {
+	drift, enabled := h.client.Drift()
+	if drift == nil {
+		drift = []model.UpstreamDrift{}
+	}
+
+	WriteJSON(w, http.StatusOK, model.UpstreamDriftResponse{Enabled: enabled, Endpoints: drift})
+}
+
+// ListPlayerCache lists the cache entries for a member
+// @Summary List cached entries for a player
+// @Description List every cached rating date for a member, including expired entries, with the stored upstream response (mchess extension)
+// @Tags admin
+// @Produce json
+// @Security AdminToken
+// @Param id path int true "Member ID"
+// @Success 200 {object} model.PlayerCacheResponse
+// @Failure 400 {object} ErrorResponse "Invalid member ID"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 403 {object} ErrorResponse "Admin API disabled"
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/cache/player/{id} [get]
+func (h *AdminHandler) ListPlayerCache(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		WriteBadRequest(w, "invalid member id")
+		return
+	}
+
+	response, err := h.cache.ListPlayer(r.Context(), id)
+	audit(r, "list player cache", err, "memberID", id)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, response)
+}
+
+// DeletePlayerCache deletes all cache entries for a member
+// @Summary Delete cached entries for a player
+// @Description Delete every cached rating date for a member (mchess extension)
+// @Tags admin
+// @Produce json
+// @Security AdminToken
+// @Param id path int true "Member ID"
+// @Success 200 {object} model.CachePurgeResponse
+// @Failure 400 {object} ErrorResponse "Invalid member ID"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 403 {object} ErrorResponse "Admin API disabled"
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/cache/player/{id} [delete]
+func (h *AdminHandler) DeletePlayerCache(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		WriteBadRequest(w, "invalid member id")
+		return
+	}
+
+	deleted, err := h.cache.DeletePlayer(r.Context(), id)
+	audit(r, "delete player cache", err, "memberID", id, "deleted", deleted)
+	writeDeleted(w, deleted, err)
+}
+
+// DeletePlayerCacheDate deletes the cache entry for a member and rating date
+// @Summary Delete a cached entry for a player and date
+// @Description Delete the cached entry for a member and the month of date (mchess extension)
+// @Tags admin
+// @Produce json
+// @Security AdminToken
+// @Param id path int true "Member ID"
+// @Param date path string true "Rating date (YYYY-MM-DD)"
+// @Success 200 {object} model.CachePurgeResponse
+// @Failure 400 {object} ErrorResponse "Invalid member ID or date"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 403 {object} ErrorResponse "Admin API disabled"
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/cache/player/{id}/date/{date} [delete]
+func (h *AdminHandler) DeletePlayerCacheDate(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		WriteBadRequest(w, "invalid member id")
+		return
+	}
+
+	// Unlike lookups, a bad date must not fall back to today
+	dateStr := chi.URLParam(r, "date")
+	date, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		WriteBadRequest(w, "invalid date, want YYYY-MM-DD")
+		return
+	}
+
+	deleted, err := h.cache.DeletePlayerDate(r.Context(), id, date)
+	audit(r, "delete player cache", err, "memberID", id, "date", dateStr, "deleted", deleted)
+	writeDeleted(w, deleted, err)
+}
+
+// RefetchPlayer fetches a player from upstream and replaces its cache entry
+// @Summary Refetch a player from upstream
+// @Description Fetch a player from upstream and replace the cache entry for the month of date, even if it has not expired (mchess extension)
+// @Tags admin
+// @Produce json
+// @Security AdminToken
+// @Param id path int true "Member ID"
+// @Param date query string false "Rating date (YYYY-MM-DD), defaults to current date"
+// @Success 200 {object} model.PlayerInfo "Player as fetched from upstream"
+// @Failure 400 {object} ErrorResponse "Invalid member ID or date"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 403 {object} ErrorResponse "Admin API disabled"
+// @Failure 404 {object} ErrorResponse "Not found upstream"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 502 {object} ErrorResponse "Upstream unavailable or invalid response"
+// @Failure 504 {object} ErrorResponse "Upstream timeout"
+// @Router /admin/cache/player/{id}/refetch [post]
+func (h *AdminHandler) RefetchPlayer(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		WriteBadRequest(w, "invalid member id")
+		return
+	}
+
+	// Unlike lookups, a bad date must not fall back to today
+	date := time.Now()
+	if dateStr := r.URL.Query().Get("date"); dateStr != "" {
+		if date, err = time.Parse("2006-01-02", dateStr); err != nil {
+			WriteBadRequest(w, "invalid date, want YYYY-MM-DD")
+			return
+		}
+	}
+
+	player, err := h.players.RefetchPlayer(r.Context(), id, date)
+	audit(r, "refetch player", err, "memberID", id, "date", date.Format("2006-01-02"))
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, player)
+}
+
+// PurgeCache deletes the entries of a cache type, optionally only those
+// fetched before a time
+// @Summary Purge a cache type
+// @Description Delete the entries of a cache type, or only those fetched before fetchedBefore (mchess extension)
+// @Tags admin
+// @Produce json
+// @Security AdminToken
+// @Param type path string true "Cache type" Enums(player, tournament, results, ratinglist, organisation)
+// @Param fetchedBefore query string false "Only delete entries fetched before this time (RFC 3339 or YYYY-MM-DD)"
+// @Success 200 {object} model.CachePurgeResponse
+// @Failure 400 {object} ErrorResponse "Unknown cache type or invalid time"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 403 {object} ErrorResponse "Admin API disabled"
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/cache/{type} [delete]
+func (h *AdminHandler) PurgeCache(w http.ResponseWriter, r *http.Request) {
+	cacheType := chi.URLParam(r, "type")
+	cacheTypes := h.cache.CacheTypes()
+	if !slices.Contains(cacheTypes, cacheType) {
+		WriteBadRequest(w, fmt.Sprintf("unknown cache type %q, want one of %s", cacheType, strings.Join(cacheTypes, ", ")))
+		return
+	}
+
+	fetchedBefore, ok := fetchedBeforeParam(w, r)
+	if !ok {
+		return
+	}
+
+	deleted, err := h.cache.Purge(r.Context(), []string{cacheType}, fetchedBefore)
+	audit(r, "purge cache", err, "cacheType", cacheType, "fetchedBefore", fetchedBefore, "deleted", deleted)
+	writeDeleted(w, deleted, err)
+}
+
+// PurgeCacheFetchedBefore deletes the entries of every cache type that were
+// fetched before a time
+// @Summary Purge entries fetched before a time
+// @Description Delete the entries of every cache type that were fetched before fetchedBefore (mchess extension)
+// @Tags admin
+// @Produce json
+// @Security AdminToken
+// @Param fetchedBefore query string true "Delete entries fetched before this time (RFC 3339 or YYYY-MM-DD)"
+// @Success 200 {object} model.CachePurgeResponse
+// @Failure 400 {object} ErrorResponse "Missing or invalid time"
+// @Failure 401 {object} ErrorResponse "Missing or invalid admin token"
+// @Failure 403 {object} ErrorResponse "Admin API disabled"
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/cache [delete]
+func (h *AdminHandler) PurgeCacheFetchedBefore(w http.ResponseWriter, r *http.Request) {
+	fetchedBefore, ok := fetchedBeforeParam(w, r)
+	if !ok {
+		return
+	}
+	// Emptying every cache is rarely what was meant
+	if fetchedBefore.IsZero() {
+		WriteBadRequest(w, "fetchedBefore is required")
+		return
+	}
+
+	deleted, err := h.cache.Purge(r.Context(), h.cache.CacheTypes(), fetchedBefore)
+	audit(r, "purge cache", err, "fetchedBefore", fetchedBefore, "deleted", deleted)
+	writeDeleted(w, deleted, err)
+}
+
+// audit logs an admin action, and the error if it failed
+func audit(r *http.Request, action string, err error, args ...any) {
+	args = append([]any{"action", action, "remoteAddr", r.RemoteAddr, "requestId", middleware.GetReqID(r.Context())}, args...)
+	if err != nil {
+		slog.Error("Admin action failed", append(args, "error", err)...)
+		return
+	}
+	slog.Info("Admin action", args...)
+}
+
+// writeDeleted writes the number of deleted cache entries, or err
+func writeDeleted(w http.ResponseWriter, deleted int64, err error) {
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+	WriteJSON(w, http.StatusOK, model.CachePurgeResponse{Deleted: deleted})
+}
+
+// fetchedBeforeParam parses the fetchedBefore query parameter, an RFC 3339
+// time or a YYYY-MM-DD date (midnight UTC). It returns the zero time if the
+// parameter is missing, and writes a 400 response and returns false if it
+// is invalid.
+func fetchedBeforeParam(w http.ResponseWriter, r *http.Request) (time.Time, bool) {
+	s := r.URL.Query().Get("fetchedBefore")
+	if s == "" {
+		return time.Time{}, true
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, true
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, true
+	}
+	WriteBadRequest(w, "invalid fetchedBefore, want RFC 3339 or YYYY-MM-DD")
+	return time.Time{}, false
+}
@@ -0,0 +1,155 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/msvens/mchess/internal/api/handlers"
+	"github.com/msvens/mchess/internal/config"
+	"github.com/msvens/mchess/internal/repository"
+	"github.com/msvens/mchess/internal/service"
+	"github.com/msvens/mchess/internal/upstream"
+)
+
+func TestAdminHandler(t *testing.T) {
+	t.Run("GetUpstreamDrift", func(t *testing.T) {
+		t.Run("Disabled_ReturnsEmpty", func(t *testing.T) {
+			handler := handlers.NewAdminHandler(nil, NewTestClient(t), nil, nil)
+			rr := MakeRequest(t, handler.GetUpstreamDrift, http.MethodGet, "/admin/upstream/drift", nil)
+
+			AssertStatus(t, rr, http.StatusOK)
+			AssertContentType(t, rr, "application/json")
+			AssertBodyContains(t, rr, `"enabled":false`)
+			AssertBodyContains(t, rr, `"endpoints":[]`)
+		})
+
+		t.Run("Enabled_FixturesMatchModels", func(t *testing.T) {
+			server := NewFakeUpstream(t)
+			client := upstream.NewClient(server.BaseURL(), 5*time.Second, 100, upstream.WithDriftDetection(true))
+			handler := handlers.NewAdminHandler(nil, client, nil, nil)
+
+			if _, err := client.GetRaw(t.Context(), "/tournament/tournament/id/6058"); err != nil {
+				t.Fatalf("GetRaw: %v", err)
+			}
+			rr := MakeRequest(t, handler.GetUpstreamDrift, http.MethodGet, "/admin/upstream/drift", nil)
+
+			AssertStatus(t, rr, http.StatusOK)
+			AssertBodyContains(t, rr, `"enabled":true`)
+			AssertBodyContains(t, rr, `"endpoints":[]`)
+		})
+	})
+
+	t.Run("InvalidParams_Return400", func(t *testing.T) {
+		// Parameters are checked before the cache is touched, so no database is needed
+		handler := handlers.NewAdminHandler(nil, NewTestClient(t), nil, nil)
+
+		tests := []struct {
+			name    string
+			handler http.HandlerFunc
+			path    string
+			params  map[string]string
+		}{
+			{"ListPlayerCache_InvalidID", handler.ListPlayerCache, "/admin/cache/player/abc", map[string]string{"id": "abc"}},
+			{"DeletePlayerCache_InvalidID", handler.DeletePlayerCache, "/admin/cache/player/abc", map[string]string{"id": "abc"}},
+			{"DeletePlayerCacheDate_InvalidDate", handler.DeletePlayerCacheDate, "/admin/cache/player/1/date/junk", map[string]string{"id": "1", "date": "junk"}},
+			{"RefetchPlayer_InvalidID", handler.RefetchPlayer, "/admin/cache/player/abc/refetch", map[string]string{"id": "abc"}},
+			{"RefetchPlayer_InvalidDate", handler.RefetchPlayer, "/admin/cache/player/1/refetch?date=2024-6-1", map[string]string{"id": "1"}},
+			{"PurgeCache_UnknownType", handler.PurgeCache, "/admin/cache/stats", map[string]string{"type": "stats"}},
+			{"PurgeCache_InvalidFetchedBefore", handler.PurgeCache, "/admin/cache/player?fetchedBefore=yesterday", map[string]string{"type": "player"}},
+			{"PurgeCacheFetchedBefore_Missing", handler.PurgeCacheFetchedBefore, "/admin/cache", nil},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				rr := MakeRequest(t, tt.handler, http.MethodDelete, tt.path, tt.params)
+				AssertStatus(t, rr, http.StatusBadRequest)
+			})
+		}
+	})
+}
+
+func TestAdminAuth(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name          string
+		token         string
+		authorization string
+		want          int
+	}{
+		{"NoTokenConfigured_Returns403", "", "Bearer secret", http.StatusForbidden},
+		{"MissingHeader_Returns401", "secret", "", http.StatusUnauthorized},
+		{"WrongToken_Returns401", "secret", "Bearer guess", http.StatusUnauthorized},
+		{"NotBearer_Returns401", "secret", "secret", http.StatusUnauthorized},
+		{"ValidToken_CallsHandler", "secret", "Bearer secret", http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodDelete, "/admin/cache/player", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rr := httptest.NewRecorder()
+			handlers.AdminAuth(tt.token)(ok).ServeHTTP(rr, req)
+
+			AssertStatus(t, rr, tt.want)
+			if tt.want == http.StatusUnauthorized && rr.Header().Get("WWW-Authenticate") == "" {
+				t.Error("401 response has no WWW-Authenticate header")
+			}
+		})
+	}
+}
+
+func TestAdminHandler_Cache(t *testing.T) {
+	SetupTestDB(t)
+	ClearTestDB(t)
+
+	database := NewTestDB(t)
+	defer database.Close()
+
+	client := NewTestClient(t)
+	playerRepo := repository.NewPlayerRepository(database.DB)
+	cfg := &config.Config{
+		Cache: config.CacheConfig{TTL: 24 * time.Hour},
+	}
+	players := service.NewPlayerService(playerRepo, client, nil, cfg)
+	cache := service.NewCacheAdminService(repository.NewCacheRepository(database.DB), playerRepo)
+	handler := handlers.NewAdminHandler(nil, client, cache, players)
+
+	params := map[string]string{"id": "500001"}
+
+	t.Run("RefetchPlayer_StoresEntry", func(t *testing.T) {
+		rr := MakeRequest(t, handler.RefetchPlayer, http.MethodPost, "/admin/cache/player/500001/refetch?date=2024-01-01", params)
+		AssertStatus(t, rr, http.StatusOK)
+		AssertBodyContains(t, rr, `"id":500001`)
+
+		rr = MakeRequest(t, handler.ListPlayerCache, http.MethodGet, "/admin/cache/player/500001", params)
+		AssertStatus(t, rr, http.StatusOK)
+		AssertBodyContains(t, rr, `"ratingDate":"2024-01-01"`)
+	})
+
+	t.Run("DeletePlayerCacheDate_DeletesEntry", func(t *testing.T) {
+		rr := MakeRequest(t, handler.DeletePlayerCacheDate, http.MethodDelete, "/admin/cache/player/500001/date/2024-01-15",
+			map[string]string{"id": "500001", "date": "2024-01-15"})
+		AssertStatus(t, rr, http.StatusOK)
+		AssertBodyContains(t, rr, `"deleted":1`)
+
+		rr = MakeRequest(t, handler.ListPlayerCache, http.MethodGet, "/admin/cache/player/500001", params)
+		AssertBodyContains(t, rr, `"entries":[]`)
+	})
+
+	t.Run("PurgeCacheFetchedBefore_KeepsNewerEntries", func(t *testing.T) {
+		MakeRequest(t, handler.RefetchPlayer, http.MethodPost, "/admin/cache/player/500001/refetch?date=2024-01-01", params)
+
+		rr := MakeRequest(t, handler.PurgeCacheFetchedBefore, http.MethodDelete, "/admin/cache?fetchedBefore=2000-01-01", nil)
+		AssertStatus(t, rr, http.StatusOK)
+		AssertBodyContains(t, rr, `"deleted":0`)
+
+		rr = MakeRequest(t, handler.PurgeCache, http.MethodDelete, "/admin/cache/player", map[string]string{"type": "player"})
+		AssertStatus(t, rr, http.StatusOK)
+		AssertBodyContains(t, rr, `"deleted":1`)
+	})
+}
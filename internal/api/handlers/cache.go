@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/msvens/mchess/internal/service"
+)
+
+// Headers describing how a response was served from the cache
+const (
+	HeaderCache          = "X-Cache" // HIT, MISS or STALE
+	HeaderAge            = "Age"     // Seconds since the oldest cached entry was fetched
+	HeaderCacheStale     = "X-Cache-Stale"
+	HeaderCacheFetchedAt = "X-Cache-Fetched-At"
+)
+
+// CacheHeaders is middleware that tells clients how a response was served.
+// Responses that used the cache get X-Cache: HIT if every entry came from
+// the cache, MISS if any was fetched from upstream, and STALE if any expired
+// entry was served because upstream was unavailable. HIT and STALE responses
+// carry an Age header with the age of the oldest entry served. STALE
+// responses also get X-Cache-Stale and X-Cache-Fetched-At, the fetch time
+// of the oldest stale entry.
+func CacheHeaders(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, status := service.WithCacheStatus(r.Context())
+		next.ServeHTTP(&cacheWriter{ResponseWriter: w, status: status}, r.WithContext(ctx))
+	})
+}
+
+// cacheWriter adds the cache headers just before the header is written
+type cacheWriter struct {
+	http.ResponseWriter
+	status      *service.CacheStatus
+	wroteHeader bool
+}
+
+func (w *cacheWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.setHeaders()
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *cacheWriter) setHeaders() {
+	status, fetchedAt := w.status.Status()
+	if status == "" {
+		return
+	}
+	w.Header().Set(HeaderCache, status)
+	if status != service.CacheMiss && !fetchedAt.IsZero() {
+		age := max(time.Since(fetchedAt), 0)
+		w.Header().Set(HeaderAge, strconv.Itoa(int(age.Seconds())))
+	}
+	if stale, staleAt := w.status.Stale(); stale {
+		w.Header().Set(HeaderCacheStale, "true")
+		w.Header().Set(HeaderCacheFetchedAt, staleAt.UTC().Format(time.RFC3339))
+	}
+}
+
+func (w *cacheWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (w *cacheWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
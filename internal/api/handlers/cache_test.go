@@ -0,0 +1,121 @@
+package handlers_test
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/msvens/mchess/internal/api/handlers"
+	"github.com/msvens/mchess/internal/config"
+	"github.com/msvens/mchess/internal/service"
+)
+
+func TestCacheHeaders(t *testing.T) {
+	client := NewTestClient(t)
+	svc := service.NewPlayerService(nil, client, nil, &config.Config{})
+	handler := handlers.NewPlayerHandler(svc, client)
+
+	t.Run("PassThrough_NoCacheHeaders", func(t *testing.T) {
+		// Player search isn't cached, so the response says nothing about the cache
+		searchPlayers := handlers.CacheHeaders(http.HandlerFunc(handler.SearchPlayers)).ServeHTTP
+		rr := MakeRequest(t, searchPlayers, http.MethodGet, "/player/fornamn/Anna/efternamn/Andersson",
+			map[string]string{"fornamn": "Anna", "efternamn": "Andersson"})
+		AssertStatus(t, rr, http.StatusOK)
+
+		if got := rr.Header().Get(handlers.HeaderCache); got != "" {
+			t.Errorf("X-Cache = %q, want none", got)
+		}
+		if got := rr.Header().Get(handlers.HeaderAge); got != "" {
+			t.Errorf("Age = %q, want none", got)
+		}
+	})
+
+	t.Run("Status", func(t *testing.T) {
+		now := time.Now()
+		oldest := now.Add(-3 * time.Hour).Truncate(time.Second)
+
+		tests := []struct {
+			name      string
+			record    func(s *service.CacheStatus)
+			wantCache string
+			wantAge   time.Duration // -1 = no Age header
+			wantStale bool
+		}{
+			{
+				name: "AllHits_AgeOfOldest",
+				record: func(s *service.CacheStatus) {
+					s.RecordHit(now.Add(-time.Hour))
+					s.RecordHit(oldest)
+					s.RecordHit(time.Time{}) // Unknown fetch time
+				},
+				wantCache: service.CacheHit,
+				wantAge:   3 * time.Hour,
+			},
+			{
+				name: "MissBeatsHit",
+				record: func(s *service.CacheStatus) {
+					s.RecordHit(oldest)
+					s.RecordMiss()
+				},
+				wantCache: service.CacheMiss,
+				wantAge:   -1,
+			},
+			{
+				name: "StaleBeatsMissAndHit",
+				record: func(s *service.CacheStatus) {
+					s.RecordHit(now.Add(-time.Hour))
+					s.RecordMiss()
+					s.RecordStale(now.Add(-2 * time.Hour))
+					s.RecordStale(oldest)
+				},
+				wantCache: service.CacheStale,
+				wantAge:   3 * time.Hour,
+				wantStale: true,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				handler := handlers.CacheHeaders(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					status, ok := service.CacheStatusFromContext(r.Context())
+					if !ok {
+						t.Fatal("No cache status in request context")
+					}
+					tt.record(status)
+					handlers.WriteJSON(w, http.StatusOK, map[string]string{})
+				}))
+				rr := MakeRequest(t, handler.ServeHTTP, http.MethodGet, "/", nil)
+				AssertStatus(t, rr, http.StatusOK)
+
+				if got := rr.Header().Get(handlers.HeaderCache); got != tt.wantCache {
+					t.Errorf("X-Cache = %q, want %q", got, tt.wantCache)
+				}
+
+				age := rr.Header().Get(handlers.HeaderAge)
+				if tt.wantAge < 0 {
+					if age != "" {
+						t.Errorf("Age = %q, want none", age)
+					}
+				} else if seconds, err := strconv.Atoi(age); err != nil ||
+					seconds < int(tt.wantAge.Seconds()) || seconds > int(tt.wantAge.Seconds())+1 {
+					t.Errorf("Age = %q, want %d", age, int(tt.wantAge.Seconds()))
+				}
+
+				stale, fetchedAt := rr.Header().Get(handlers.HeaderCacheStale), rr.Header().Get(handlers.HeaderCacheFetchedAt)
+				if !tt.wantStale {
+					if stale != "" || fetchedAt != "" {
+						t.Errorf("Stale headers = %q, %q, want none", stale, fetchedAt)
+					}
+					return
+				}
+				if stale != "true" {
+					t.Errorf("X-Cache-Stale = %q, want true", stale)
+				}
+				if want := oldest.UTC().Format(time.RFC3339); fetchedAt != want {
+					t.Errorf("X-Cache-Fetched-At = %q, want %q", fetchedAt, want)
+				}
+			})
+		}
+	})
+}
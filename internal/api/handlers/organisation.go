@@ -7,107 +7,148 @@ import (
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/msvens/mchess/internal/service"
 	"github.com/msvens/mchess/internal/upstream"
 )
 
-// OrganisationHandler handles organisation-related requests (pass-through)
+// OrganisationHandler handles organisation-related requests
 type OrganisationHandler struct {
-	client *upstream.Client
+	service *service.OrganisationService
+	client  *upstream.Client // For pass-through when no caching needed
 }
 
 // NewOrganisationHandler creates a new organisation handler
-func NewOrganisationHandler(client *upstream.Client) *OrganisationHandler {
-	return &OrganisationHandler{client: client}
+func NewOrganisationHandler(service *service.OrganisationService, client *upstream.Client) *OrganisationHandler {
+	return &OrganisationHandler{service: service, client: client}
 }
 
 // GetFederation returns Swedish Chess Federation info
 // @Summary Get federation info
-// @Description Get Swedish Chess Federation (SSF) information
+// @Description Get Swedish Chess Federation (SSF) information (cached)
 // @Tags organisation
 // @Produce json
-// @Success 200 {object} object
+// @Success 200 {object} model.Federation
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 504 {object} ErrorResponse
 // @Router /organisation/federation [get]
 func (h *OrganisationHandler) GetFederation(w http.ResponseWriter, r *http.Request) {
-	data, err := h.client.GetRaw(r.Context(), "/organisation/federation")
+	data, err := h.service.GetFederationRaw(r.Context())
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteError(w, err)
 		return
 	}
+
 	WriteRawJSON(w, http.StatusOK, data)
 }
 
 // GetDistricts returns all districts
 // @Summary Get all districts
-// @Description Get all chess districts
+// @Description Get all chess districts (cached)
 // @Tags organisation
 // @Produce json
-// @Success 200 {array} object
+// @Success 200 {array} model.District
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 504 {object} ErrorResponse
 // @Router /organisation/districts [get]
 func (h *OrganisationHandler) GetDistricts(w http.ResponseWriter, r *http.Request) {
-	data, err := h.client.GetRaw(r.Context(), "/organisation/districts")
+	data, err := h.service.GetDistrictsRaw(r.Context())
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteError(w, err)
 		return
 	}
+
 	WriteRawJSON(w, http.StatusOK, data)
 }
 
 // GetClubsInDistrict returns clubs in a district
 // @Summary Get clubs in district
-// @Description Get all clubs in a specific district
+// @Description Get all clubs in a specific district (cached)
 // @Tags organisation
 // @Produce json
 // @Param districtid path int true "District ID"
-// @Success 200 {array} object
+// @Success 200 {array} model.Club
 // @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 504 {object} ErrorResponse
 // @Router /organisation/district/clubs/{districtid} [get]
 func (h *OrganisationHandler) GetClubsInDistrict(w http.ResponseWriter, r *http.Request) {
 	districtIDStr := chi.URLParam(r, "districtid")
 	districtID, err := strconv.Atoi(districtIDStr)
 	if err != nil {
-		WriteError(w, http.StatusBadRequest, "invalid district id")
+		WriteBadRequest(w, "invalid district id")
 		return
 	}
 
-	path := fmt.Sprintf("/organisation/district/clubs/%d", districtID)
-	data, err := h.client.GetRaw(r.Context(), path)
+	data, err := h.service.GetClubsInDistrictRaw(r.Context(), districtID)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteError(w, err)
 		return
 	}
+
 	WriteRawJSON(w, http.StatusOK, data)
 }
 
 // GetClub returns a specific club
 // @Summary Get club
-// @Description Get a specific club by ID
+// @Description Get a specific club by ID (cached)
 // @Tags organisation
 // @Produce json
 // @Param clubid path int true "Club ID"
-// @Success 200 {object} object
+// @Success 200 {object} model.Club
 // @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 504 {object} ErrorResponse
 // @Router /organisation/club/{clubid} [get]
 func (h *OrganisationHandler) GetClub(w http.ResponseWriter, r *http.Request) {
 	clubIDStr := chi.URLParam(r, "clubid")
 	clubID, err := strconv.Atoi(clubIDStr)
 	if err != nil {
-		WriteError(w, http.StatusBadRequest, "invalid club id")
+		WriteBadRequest(w, "invalid club id")
 		return
 	}
 
-	path := fmt.Sprintf("/organisation/club/%d", clubID)
-	data, err := h.client.GetRaw(r.Context(), path)
+	data, err := h.service.GetClubRaw(r.Context(), clubID)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteError(w, err)
 		return
 	}
+
 	WriteRawJSON(w, http.StatusOK, data)
 }
 
+// GetClubs handles GET /organisation/club/batch?ids=1,2,3
+// @Summary Batch fetch multiple clubs
+// @Description Fetch multiple clubs in a single request by providing comma-separated club IDs. Maximum 100 IDs per request (batch.maxSize). All lookups are cached. If the request deadline is near, partial results are returned with errors for the IDs that were not fetched. (mchess extension)
+// @Tags organisation
+// @Produce json
+// @Param ids query string true "Comma-separated club IDs (max 100)" example:"101,102,103"
+// @Success 200 {object} model.ClubsResponse "Clubs with any errors for failed lookups"
+// @Failure 400 {object} ErrorResponse "Invalid request (missing/invalid IDs, too many IDs)"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /organisation/club/batch [get]
+func (h *OrganisationHandler) GetClubs(w http.ResponseWriter, r *http.Request) {
+	ids, ok := batchIDs(w, r, h.service.MaxBatchSize())
+	if !ok {
+		return
+	}
+
+	response, err := h.service.GetClubs(r.Context(), ids)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, response)
+}
+
 // ClubNameExists checks if a club name exists
 // @Summary Check if club name exists
 // @Description Check if a club name exists (other than for the given club ID)
@@ -117,21 +158,24 @@ func (h *OrganisationHandler) GetClub(w http.ResponseWriter, r *http.Request) {
 // @Param id path int true "Club ID to exclude"
 // @Success 200 {boolean} bool
 // @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 504 {object} ErrorResponse
 // @Router /organisation/club/exists/{name}/{id} [get]
 func (h *OrganisationHandler) ClubNameExists(w http.ResponseWriter, r *http.Request) {
 	name := chi.URLParam(r, "name")
 	clubIDStr := chi.URLParam(r, "id")
 	clubID, err := strconv.Atoi(clubIDStr)
 	if err != nil {
-		WriteError(w, http.StatusBadRequest, "invalid club id")
+		WriteBadRequest(w, "invalid club id")
 		return
 	}
 
 	path := fmt.Sprintf("/organisation/club/exists/%s/%d", url.PathEscape(name), clubID)
 	data, err := h.client.GetRaw(r.Context(), path)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteError(w, err)
 		return
 	}
 	WriteRawJSON(w, http.StatusOK, data)
@@ -3,52 +3,24 @@ package handlers_test
 import (
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/msvens/mchess/internal/api/handlers"
+	"github.com/msvens/mchess/internal/config"
+	"github.com/msvens/mchess/internal/model"
+	"github.com/msvens/mchess/internal/repository"
+	"github.com/msvens/mchess/internal/service"
+	"github.com/msvens/mchess/internal/upstream"
 )
 
 func TestOrganisationHandler(t *testing.T) {
 	client := NewTestClient(t)
-	handler := handlers.NewOrganisationHandler(client)
 
-	t.Run("GetFederation", func(t *testing.T) {
-		t.Run("ReturnsSuccess", func(t *testing.T) {
-			// Scenario 1: Correct input - federation info
-			rr := MakeRequest(t, handler.GetFederation, http.MethodGet,
-				"/organisation/federation",
-				nil)
-
-			AssertStatus(t, rr, http.StatusOK)
-			AssertContentType(t, rr, "application/json")
-			AssertBodyNotEmpty(t, rr)
-		})
-	})
-
-	t.Run("GetDistricts", func(t *testing.T) {
-		t.Run("ReturnsSuccess", func(t *testing.T) {
-			// Scenario 1: Returns list of districts
-			rr := MakeRequest(t, handler.GetDistricts, http.MethodGet,
-				"/organisation/districts",
-				nil)
-
-			AssertStatus(t, rr, http.StatusOK)
-			AssertContentType(t, rr, "application/json")
-			AssertBodyNotEmpty(t, rr)
-		})
-	})
+	// For simple tests that don't need caching, create handler without service
+	// Cached endpoints are covered by TestOrganisationHandler_Caching
+	handler := handlers.NewOrganisationHandler(nil, client)
 
 	t.Run("GetClubsInDistrict", func(t *testing.T) {
-		t.Run("ValidDistrictID_ReturnsSuccess", func(t *testing.T) {
-			// Scenario 1: Correct input - district 1 (Stockholm)
-			rr := MakeRequest(t, handler.GetClubsInDistrict, http.MethodGet,
-				"/organisation/district/clubs/1",
-				map[string]string{"districtid": "1"})
-
-			AssertStatus(t, rr, http.StatusOK)
-			AssertContentType(t, rr, "application/json")
-			AssertBodyNotEmpty(t, rr)
-		})
-
 		t.Run("InvalidDistrictID_ReturnsError", func(t *testing.T) {
 			// Scenario 2: Wrong input - non-existent district ID
 			// TODO: Check what upstream returns for invalid district
@@ -90,8 +62,13 @@ func TestOrganisationHandler(t *testing.T) {
 
 	t.Run("ClubNameExists", func(t *testing.T) {
 		t.Run("ValidParams_ReturnsSuccess", func(t *testing.T) {
-			// TODO: Find valid test params
-			t.Skip("TODO: Implement")
+			rr := MakeRequest(t, handler.ClubNameExists, http.MethodGet,
+				"/organisation/club/exists/SK%20Rockaden/101",
+				map[string]string{"name": "SK Rockaden", "id": "101"})
+
+			AssertStatus(t, rr, http.StatusOK)
+			AssertContentType(t, rr, "application/json")
+			AssertBodyContains(t, rr, "true")
 		})
 
 		t.Run("MalformedClubID_Returns400", func(t *testing.T) {
@@ -103,3 +80,111 @@ func TestOrganisationHandler(t *testing.T) {
 		})
 	})
 }
+
+// TestOrganisationHandler_Caching tests caching behavior (requires database)
+func TestOrganisationHandler_Caching(t *testing.T) {
+	SetupTestDB(t)
+	ClearTestDB(t)
+
+	database := NewTestDB(t)
+	defer database.Close()
+
+	client := NewTestClient(t)
+	repo := repository.NewOrganisationRepository(database.DB)
+	cfg := &config.Config{
+		Cache: config.CacheConfig{OrganisationTTL: 24 * time.Hour},
+	}
+	svc := service.NewOrganisationService(repo, client, nil, cfg)
+	handler := handlers.NewOrganisationHandler(svc, client)
+
+	t.Run("GetFederation", func(t *testing.T) {
+		t.Run("ReturnsSuccess", func(t *testing.T) {
+			// Scenario 1: Correct input - federation info
+			rr := MakeRequest(t, handler.GetFederation, http.MethodGet,
+				"/organisation/federation",
+				nil)
+
+			AssertStatus(t, rr, http.StatusOK)
+			AssertContentType(t, rr, "application/json")
+			AssertBodyNotEmpty(t, rr)
+		})
+	})
+
+	t.Run("GetDistricts", func(t *testing.T) {
+		t.Run("ReturnsSuccess", func(t *testing.T) {
+			// Scenario 1: Returns list of districts
+			rr := MakeRequest(t, handler.GetDistricts, http.MethodGet,
+				"/organisation/districts",
+				nil)
+
+			AssertStatus(t, rr, http.StatusOK)
+			AssertContentType(t, rr, "application/json")
+			AssertBodyNotEmpty(t, rr)
+		})
+	})
+
+	t.Run("GetClubsInDistrict", func(t *testing.T) {
+		t.Run("ValidDistrictID_ReturnsSuccess", func(t *testing.T) {
+			// Scenario 1: Correct input - district 1 (Stockholm)
+			rr := MakeRequest(t, handler.GetClubsInDistrict, http.MethodGet,
+				"/organisation/district/clubs/1",
+				map[string]string{"districtid": "1"})
+
+			AssertStatus(t, rr, http.StatusOK)
+			AssertContentType(t, rr, "application/json")
+			AssertBodyNotEmpty(t, rr)
+		})
+	})
+
+	t.Run("FirstRequest_StoresInCache", func(t *testing.T) {
+		var count int
+		err := database.QueryRow(
+			`SELECT COUNT(*) FROM organisation_cache WHERE kind = 'districtclubs' AND id = 1`,
+		).Scan(&count)
+		if err != nil {
+			t.Fatalf("Failed to query cache: %v", err)
+		}
+		if count != 1 {
+			t.Errorf("Cache rows: got %d, want 1", count)
+		}
+	})
+
+	t.Run("CachedResponse_MatchesUpstream", func(t *testing.T) {
+		// Served from the cache, with every upstream field kept
+		rr := MakeRequest(t, handler.GetClubsInDistrict, http.MethodGet,
+			"/organisation/district/clubs/1",
+			map[string]string{"districtid": "1"})
+
+		AssertStatus(t, rr, http.StatusOK)
+		AssertUpstreamJSON(t, rr, client, "/organisation/district/clubs/1")
+	})
+}
+
+// TestOrganisationHandler_Batch tests partial batch results (requires database)
+func TestOrganisationHandler_Batch(t *testing.T) {
+	SetupTestDB(t)
+	ClearTestDB(t)
+
+	database := NewTestDB(t)
+	defer database.Close()
+
+	server := NewFakeUpstream(t)
+	client := upstream.NewClient(server.BaseURL(), 5*time.Second, 100)
+	cfg := &config.Config{
+		Cache: config.CacheConfig{OrganisationTTL: 24 * time.Hour},
+	}
+	svc := service.NewOrganisationService(repository.NewOrganisationRepository(database.DB), client, nil, cfg)
+	handler := handlers.NewOrganisationHandler(svc, client)
+
+	t.Run("UnknownID_PartialResults", func(t *testing.T) {
+		rr := MakeRequestWithQuery(t, handler.GetClubs, http.MethodGet,
+			"/organisation/club/batch?ids=101,99999", nil)
+
+		AssertStatus(t, rr, http.StatusOK)
+		var response model.ClubsResponse
+		DecodeBatchResponse(t, rr, &response, 99999)
+		if len(response.Clubs) != 1 || response.Clubs[0].ID != 101 {
+			t.Errorf("Clubs: got %v, want 101", response.Clubs)
+		}
+	})
+}
@@ -1,12 +1,15 @@
 package handlers
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/msvens/mchess/internal/model"
 	"github.com/msvens/mchess/internal/service"
 	"github.com/msvens/mchess/internal/upstream"
 )
@@ -15,8 +18,9 @@ import (
 // @Description API error response
 // @name ErrorResponse
 type ErrorResponse struct {
-	Error string `json:"error" example:"invalid player id"`
-	Code  int    `json:"code" example:"400"`
+	Error   string   `json:"error" example:"invalid player id"`
+	Code    int      `json:"code" example:"400"`
+	Details []string `json:"details,omitempty" example:"$.elo.rating: want integer, got string"` // Why an upstream response was rejected
 }
 
 // PlayerHandler handles player-related HTTP requests
@@ -37,15 +41,19 @@ func NewPlayerHandler(service *service.PlayerService, client *upstream.Client) *
 // @Produce json
 // @Param id path int true "Member ID (Swedish Chess Federation ID)"
 // @Param date path string true "Rating date (YYYY-MM-DD)"
+// @Param meta query bool false "Include the _cached and _cachedAt fields (default true)"
 // @Success 200 {object} model.PlayerInfo "Player information"
 // @Failure 400 {object} ErrorResponse "Invalid player ID"
+// @Failure 404 {object} ErrorResponse "Not found upstream"
 // @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 502 {object} ErrorResponse "Upstream unavailable or invalid response"
+// @Failure 504 {object} ErrorResponse "Upstream timeout"
 // @Router /player/{id}/date/{date} [get]
 func (h *PlayerHandler) GetPlayer(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		WriteError(w, http.StatusBadRequest, "invalid player id")
+		WriteBadRequest(w, "invalid player id")
 		return
 	}
 
@@ -54,9 +62,12 @@ func (h *PlayerHandler) GetPlayer(w http.ResponseWriter, r *http.Request) {
 
 	player, err := h.service.GetPlayer(r.Context(), id, date)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteError(w, err)
 		return
 	}
+	if !cacheMeta(r) {
+		player = withoutCacheMeta(player)
+	}
 
 	WriteJSON(w, http.StatusOK, player)
 }
@@ -68,15 +79,19 @@ func (h *PlayerHandler) GetPlayer(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path int true "FIDE ID"
 // @Param date path string true "Rating date (YYYY-MM-DD)"
+// @Param meta query bool false "Include the _cached and _cachedAt fields (default true)"
 // @Success 200 {object} model.PlayerInfo "Player information"
 // @Failure 400 {object} ErrorResponse "Invalid FIDE ID"
+// @Failure 404 {object} ErrorResponse "Not found upstream"
 // @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 502 {object} ErrorResponse "Upstream unavailable or invalid response"
+// @Failure 504 {object} ErrorResponse "Upstream timeout"
 // @Router /player/fideid/{id}/date/{date} [get]
 func (h *PlayerHandler) GetPlayerByFideID(w http.ResponseWriter, r *http.Request) {
 	fideIDStr := chi.URLParam(r, "id")
 	fideID, err := strconv.Atoi(fideIDStr)
 	if err != nil {
-		WriteError(w, http.StatusBadRequest, "invalid fide id")
+		WriteBadRequest(w, "invalid fide id")
 		return
 	}
 
@@ -85,9 +100,12 @@ func (h *PlayerHandler) GetPlayerByFideID(w http.ResponseWriter, r *http.Request
 
 	player, err := h.service.GetPlayerByFideID(r.Context(), fideID, date)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteError(w, err)
 		return
 	}
+	if !cacheMeta(r) {
+		player = withoutCacheMeta(player)
+	}
 
 	WriteJSON(w, http.StatusOK, player)
 }
@@ -100,7 +118,10 @@ func (h *PlayerHandler) GetPlayerByFideID(w http.ResponseWriter, r *http.Request
 // @Param fornamn path string true "First name"
 // @Param efternamn path string true "Last name"
 // @Success 200 {array} model.PlayerInfo "List of matching players"
+// @Failure 404 {object} ErrorResponse "Not found upstream"
 // @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 502 {object} ErrorResponse "Upstream unavailable or invalid response"
+// @Failure 504 {object} ErrorResponse "Upstream timeout"
 // @Router /player/fornamn/{fornamn}/efternamn/{efternamn} [get]
 func (h *PlayerHandler) SearchPlayers(w http.ResponseWriter, r *http.Request) {
 	firstName := chi.URLParam(r, "fornamn")
@@ -108,7 +129,7 @@ func (h *PlayerHandler) SearchPlayers(w http.ResponseWriter, r *http.Request) {
 
 	players, err := h.service.SearchPlayers(r.Context(), firstName, lastName)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteError(w, err)
 		return
 	}
 
@@ -117,45 +138,97 @@ func (h *PlayerHandler) SearchPlayers(w http.ResponseWriter, r *http.Request) {
 
 // GetPlayers handles GET /player/batch?ids=1,2,3&date=2024-06-01
 // @Summary Batch fetch multiple players
-// @Description Fetch multiple players in a single request by providing comma-separated member IDs. Maximum 100 IDs per request. All lookups are cached.
+// @Description Fetch multiple players in a single request by providing comma-separated member IDs. Maximum 100 IDs per request (batch.maxSize). All lookups are cached. If the request deadline is near, partial results are returned with errors for the IDs that were not fetched.
 // @Tags player
 // @Produce json
 // @Param ids query string true "Comma-separated member IDs (max 100)" example:"12345,67890,11111"
 // @Param date query string false "Rating date (YYYY-MM-DD), defaults to current date"
+// @Param meta query bool false "Include the _cached and _cachedAt fields (default true)"
 // @Success 200 {object} model.PlayersResponse "Players with any errors for failed lookups"
 // @Failure 400 {object} ErrorResponse "Invalid request (missing/invalid IDs, too many IDs)"
+// @Failure 404 {object} ErrorResponse "Not found upstream"
 // @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 502 {object} ErrorResponse "Upstream unavailable or invalid response"
+// @Failure 504 {object} ErrorResponse "Upstream timeout"
 // @Router /player/batch [get]
 func (h *PlayerHandler) GetPlayers(w http.ResponseWriter, r *http.Request) {
-	idsStr := r.URL.Query().Get("ids")
-	if idsStr == "" {
-		WriteError(w, http.StatusBadRequest, "ids parameter is required")
+	ids, ok := batchIDs(w, r, h.service.MaxBatchSize())
+	if !ok {
 		return
 	}
 
-	ids, err := parseIDs(idsStr)
+	date := parseDate(r.URL.Query().Get("date"))
+
+	response, err := h.service.GetPlayers(r.Context(), ids, date)
 	if err != nil {
-		WriteError(w, http.StatusBadRequest, "invalid ids format")
+		WriteError(w, err)
 		return
 	}
+	if !cacheMeta(r) {
+		stripCacheMeta(response.Players)
+	}
 
-	if len(ids) == 0 {
-		WriteError(w, http.StatusBadRequest, "at least one id is required")
+	WriteJSON(w, http.StatusOK, response)
+}
+
+// PostPlayers handles POST /player/batch
+// @Summary Batch fetch players with per-player dates
+// @Description Fetch multiple players in a single request, each by member ID or FIDE ID and with its own rating date. Maximum 100 entries per request (batch.maxSize). All lookups are cached. Failed entries are reported in errors.
+// @Tags player
+// @Accept json
+// @Produce json
+// @Param players body []model.PlayerBatchItem true "Players to look up, each with id or fideId"
+// @Param meta query bool false "Include the _cached and _cachedAt fields (default true)"
+// @Success 200 {object} model.PlayersResponse "Players with any errors for failed lookups"
+// @Failure 400 {object} ErrorResponse "Invalid request (malformed body, missing ids, invalid dates, too many entries)"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /player/batch [post]
+func (h *PlayerHandler) PostPlayers(w http.ResponseWriter, r *http.Request) {
+	var items []model.PlayerBatchItem
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBatchBodySize)).Decode(&items); err != nil {
+		WriteBadRequest(w, "invalid request body")
 		return
 	}
 
-	if len(ids) > 100 {
-		WriteError(w, http.StatusBadRequest, "maximum 100 ids allowed")
+	if len(items) == 0 {
+		WriteBadRequest(w, "at least one player is required")
 		return
 	}
 
-	date := parseDate(r.URL.Query().Get("date"))
+	if maxSize := h.service.MaxBatchSize(); maxSize > 0 && len(items) > maxSize {
+		WriteBadRequest(w, fmt.Sprintf("maximum %d players allowed", maxSize))
+		return
+	}
 
-	response, err := h.service.GetPlayers(r.Context(), ids, date)
+	lookups := make([]service.PlayerLookup, len(items))
+	for i, item := range items {
+		if (item.ID > 0) == (item.FideID > 0) {
+			WriteBadRequest(w, fmt.Sprintf("entry %d: exactly one of id and fideId is required", i))
+			return
+		}
+		date := time.Now()
+		if item.Date != "" {
+			var err error
+			if date, err = parseRatingDate(item.Date); err != nil {
+				WriteBadRequest(w, fmt.Sprintf("entry %d: invalid date, want YYYY-MM-DD", i))
+				return
+			}
+		}
+		lookups[i] = service.PlayerLookup{
+			MemberID: item.ID,
+			FideID:   item.FideID,
+			Date:     date,
+		}
+	}
+
+	response, err := h.service.GetPlayersByLookup(r.Context(), lookups)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteError(w, err)
 		return
 	}
+	if !cacheMeta(r) {
+		stripCacheMeta(response.Players)
+	}
 
 	WriteJSON(w, http.StatusOK, response)
 }
@@ -169,15 +242,19 @@ func (h *PlayerHandler) GetPlayers(w http.ResponseWriter, r *http.Request) {
 // @Param from query string false "Start date (YYYY-MM-DD or YYYY-MM)"
 // @Param to query string false "End date (YYYY-MM-DD or YYYY-MM)"
 // @Param months query int false "Number of months back from today (alternative to from/to)"
+// @Param meta query bool false "Include the _cached and _cachedAt fields (default true)"
 // @Success 200 {object} model.RatingHistoryResponse "Rating history sorted newest first"
 // @Failure 400 {object} ErrorResponse "Invalid player ID"
+// @Failure 404 {object} ErrorResponse "Not found upstream"
 // @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 502 {object} ErrorResponse "Upstream unavailable or invalid response"
+// @Failure 504 {object} ErrorResponse "Upstream timeout"
 // @Router /player/{id}/ratings [get]
 func (h *PlayerHandler) GetPlayerRatings(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		WriteError(w, http.StatusBadRequest, "invalid player id")
+		WriteBadRequest(w, "invalid player id")
 		return
 	}
 
@@ -186,15 +263,21 @@ func (h *PlayerHandler) GetPlayerRatings(w http.ResponseWriter, r *http.Request)
 
 	response, err := h.service.GetPlayerRatings(r.Context(), id, fromDate, toDate)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteError(w, err)
 		return
 	}
+	if !cacheMeta(r) {
+		stripCacheMeta(response.Ratings)
+	}
 
 	WriteJSON(w, http.StatusOK, response)
 }
 
 // Internal helper functions
 
+// maxBatchBodySize limits the size of POST batch request bodies
+const maxBatchBodySize = 1 << 20
+
 func parseDate(dateStr string) time.Time {
 	if dateStr == "" {
 		return time.Now()
@@ -213,6 +296,67 @@ func parseDate(dateStr string) time.Time {
 	return time.Now()
 }
 
+// parseRatingDate parses a required rating date (YYYY-MM-DD or YYYY-MM).
+// Unlike parseDate it does not fall back to today, so a malformed date
+// cannot be cached as the current month.
+func parseRatingDate(dateStr string) (time.Time, error) {
+	t, err := time.Parse("2006-01-02", dateStr)
+	if err != nil {
+		t, err = time.Parse("2006-01", dateStr)
+	}
+	return t, err
+}
+
+// cacheMeta reports whether player responses include the _cached and
+// _cachedAt fields. Clients that expect exact upstream DTOs can turn them
+// off with meta=false.
+func cacheMeta(r *http.Request) bool {
+	meta, err := strconv.ParseBool(r.URL.Query().Get("meta"))
+	return err != nil || meta
+}
+
+// withoutCacheMeta returns a copy of player without the cache metadata
+func withoutCacheMeta(player *model.PlayerInfo) *model.PlayerInfo {
+	p := *player
+	p.Cached, p.CachedAt = false, nil
+	return &p
+}
+
+// stripCacheMeta removes the cache metadata from players
+func stripCacheMeta(players []model.PlayerInfo) {
+	for i := range players {
+		players[i].Cached, players[i].CachedAt = false, nil
+	}
+}
+
+// batchIDs parses the ids query parameter of a batch request, writing a
+// 400 response and returning false if it is missing, invalid or too long
+func batchIDs(w http.ResponseWriter, r *http.Request, maxSize int) ([]int, bool) {
+	idsStr := r.URL.Query().Get("ids")
+	if idsStr == "" {
+		WriteBadRequest(w, "ids parameter is required")
+		return nil, false
+	}
+
+	ids, err := parseIDs(idsStr)
+	if err != nil {
+		WriteBadRequest(w, "invalid ids format")
+		return nil, false
+	}
+
+	if len(ids) == 0 {
+		WriteBadRequest(w, "at least one id is required")
+		return nil, false
+	}
+
+	if maxSize > 0 && len(ids) > maxSize {
+		WriteBadRequest(w, fmt.Sprintf("maximum %d ids allowed", maxSize))
+		return nil, false
+	}
+
+	return ids, true
+}
+
 func parseIDs(idsStr string) ([]int, error) {
 	parts := strings.Split(idsStr, ",")
 	ids := make([]int, 0, len(parts))
@@ -2,8 +2,10 @@ package handlers_test
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -68,7 +70,62 @@ func TestPlayerHandler(t *testing.T) {
 		})
 
 		t.Run("TooManyIDs_Returns400", func(t *testing.T) {
-			t.Skip("TODO: Implement - needs full handler setup")
+			// The size check runs before any lookup, so no repository is needed
+			cfg := &config.Config{Batch: config.BatchConfig{MaxSize: 2}}
+			handler := handlers.NewPlayerHandler(service.NewPlayerService(nil, client, nil, cfg), client)
+
+			rr := MakeRequestWithQuery(t, handler.GetPlayers, http.MethodGet,
+				"/player/batch?ids=1,2,3", nil)
+
+			AssertStatus(t, rr, http.StatusBadRequest)
+			AssertBodyContains(t, rr, "maximum 2 ids allowed")
+		})
+	})
+
+	t.Run("PostPlayers_Batch", func(t *testing.T) {
+		// Validation runs before any lookup, so no repository is needed
+		cfg := &config.Config{Batch: config.BatchConfig{MaxSize: 2}}
+		handler := handlers.NewPlayerHandler(service.NewPlayerService(nil, client, nil, cfg), client)
+
+		post := func(body string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPost, "/player/batch", strings.NewReader(body))
+			rr := httptest.NewRecorder()
+			handler.PostPlayers(rr, req)
+			return rr
+		}
+
+		t.Run("MalformedBody_Returns400", func(t *testing.T) {
+			rr := post(`{"id": 1}`)
+			AssertStatus(t, rr, http.StatusBadRequest)
+			AssertBodyContains(t, rr, "invalid request body")
+		})
+
+		t.Run("EmptyList_Returns400", func(t *testing.T) {
+			rr := post(`[]`)
+			AssertStatus(t, rr, http.StatusBadRequest)
+		})
+
+		t.Run("TooManyPlayers_Returns400", func(t *testing.T) {
+			rr := post(`[{"id": 1}, {"id": 2}, {"id": 3}]`)
+			AssertStatus(t, rr, http.StatusBadRequest)
+			AssertBodyContains(t, rr, "maximum 2 players allowed")
+		})
+
+		t.Run("NeitherIDNorFideID_Returns400", func(t *testing.T) {
+			rr := post(`[{"id": 1}, {"date": "2024-06-01"}]`)
+			AssertStatus(t, rr, http.StatusBadRequest)
+			AssertBodyContains(t, rr, "entry 1")
+		})
+
+		t.Run("BothIDAndFideID_Returns400", func(t *testing.T) {
+			rr := post(`[{"id": 1, "fideId": 1503014}]`)
+			AssertStatus(t, rr, http.StatusBadRequest)
+		})
+
+		t.Run("MalformedDate_Returns400", func(t *testing.T) {
+			rr := post(`[{"id": 1, "date": "2024-06-01"}, {"id": 2, "date": "June"}]`)
+			AssertStatus(t, rr, http.StatusBadRequest)
+			AssertBodyContains(t, rr, "entry 1: invalid date")
 		})
 	})
 
@@ -81,6 +138,36 @@ func TestPlayerHandler(t *testing.T) {
 	_ = client
 }
 
+// TestBatchIDs tests the ids parameter validation shared by the GET batch
+// endpoints. It runs before any lookup, so no repository is needed.
+func TestBatchIDs(t *testing.T) {
+	client := NewTestClient(t)
+	cfg := &config.Config{Batch: config.BatchConfig{MaxSize: 2}}
+	handler := handlers.NewTournamentHandler(service.NewTournamentService(nil, client, nil, cfg), client)
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"MissingIDs", "", "ids parameter is required"},
+		{"MalformedIDs", "?ids=1,abc", "invalid ids format"},
+		{"NoIDs", "?ids=,%20,", "at least one id is required"},
+		{"TooManyIDs", "?ids=1,2,3", "maximum 2 ids allowed"},
+		{"TooManyIDsWithSpaces", "?ids=1,%202%20,3", "maximum 2 ids allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := MakeRequestWithQuery(t, handler.GetTournaments, http.MethodGet,
+				"/tournament/tournament/batch"+tt.query, nil)
+
+			AssertStatus(t, rr, http.StatusBadRequest)
+			AssertBodyContains(t, rr, tt.want)
+		})
+	}
+}
+
 // TestPlayerHandler_Caching tests caching behavior (requires database)
 func TestPlayerHandler_Caching(t *testing.T) {
 	// Setup test database
@@ -95,7 +182,7 @@ func TestPlayerHandler_Caching(t *testing.T) {
 	cfg := &config.Config{
 		Cache: config.CacheConfig{TTL: 24 * time.Hour},
 	}
-	svc := service.NewPlayerService(repo, client, cfg)
+	svc := service.NewPlayerService(repo, client, nil, cfg)
 	handler := handlers.NewPlayerHandler(svc, client)
 
 	t.Run("FirstRequest_CachesMiss_FetchesFromUpstream", func(t *testing.T) {
@@ -141,7 +228,67 @@ func TestPlayerHandler_Caching(t *testing.T) {
 		t.Skip("TODO: Implement")
 	})
 
-	_ = handler
+	t.Run("CacheHeaders_MissThenHit", func(t *testing.T) {
+		getPlayer := handlers.CacheHeaders(http.HandlerFunc(handler.GetPlayer)).ServeHTTP
+		params := map[string]string{"id": "500002", "date": "2024-01-01"}
+
+		rr := MakeRequest(t, getPlayer, http.MethodGet, "/player/500002/date/2024-01-01", params)
+		AssertStatus(t, rr, http.StatusOK)
+		if got := rr.Header().Get(handlers.HeaderCache); got != "MISS" {
+			t.Errorf("First request X-Cache = %q, want MISS", got)
+		}
+		if strings.Contains(rr.Body.String(), `"_cached"`) {
+			t.Errorf("Upstream response has cache metadata: %s", rr.Body.String())
+		}
+
+		rr = MakeRequest(t, getPlayer, http.MethodGet, "/player/500002/date/2024-01-01", params)
+		AssertStatus(t, rr, http.StatusOK)
+		if got := rr.Header().Get(handlers.HeaderCache); got != "HIT" {
+			t.Errorf("Second request X-Cache = %q, want HIT", got)
+		}
+		if rr.Header().Get(handlers.HeaderAge) == "" {
+			t.Error("Second request has no Age header")
+		}
+		AssertBodyContains(t, rr, `"_cached":true`)
+		AssertBodyContains(t, rr, `"_cachedAt":`)
+	})
+
+	t.Run("PostBatch_FideIDFailure_OmitsID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/player/batch",
+			strings.NewReader(`[{"id": 500001, "date": "2024-01-01"}, {"fideId": 99999999, "date": "2024-01-01"}]`))
+		rr := httptest.NewRecorder()
+		handler.PostPlayers(rr, req)
+
+		AssertStatus(t, rr, http.StatusOK)
+		var response struct {
+			Players []json.RawMessage        `json:"players"`
+			Errors  []map[string]interface{} `json:"errors"`
+		}
+		if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Decode response: %v", err)
+		}
+		if len(response.Players) != 1 || len(response.Errors) != 1 {
+			t.Fatalf("Got %d players and %d errors, want 1 and 1: %s",
+				len(response.Players), len(response.Errors), rr.Body.String())
+		}
+		if _, ok := response.Errors[0]["id"]; ok {
+			t.Errorf("FIDE ID error has an id: %v", response.Errors[0])
+		}
+		if got := response.Errors[0]["fideId"]; got != float64(99999999) {
+			t.Errorf("Error fideId = %v, want 99999999", got)
+		}
+	})
+
+	t.Run("MetaFalse_OmitsCacheMetadata", func(t *testing.T) {
+		params := map[string]string{"id": "500002", "date": "2024-01-01"}
+		MakeRequest(t, handler.GetPlayer, http.MethodGet, "/player/500002/date/2024-01-01", params)
+
+		rr := MakeRequest(t, handler.GetPlayer, http.MethodGet, "/player/500002/date/2024-01-01?meta=false", params)
+		AssertStatus(t, rr, http.StatusOK)
+		if strings.Contains(rr.Body.String(), `"_cached`) {
+			t.Errorf("meta=false response has cache metadata: %s", rr.Body.String())
+		}
+	})
 }
 
 // Helper to make request with full URL (including query params)
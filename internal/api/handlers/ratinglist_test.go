@@ -1,33 +1,23 @@
 package handlers_test
 
 import (
+	"database/sql"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/msvens/mchess/internal/api/handlers"
+	"github.com/msvens/mchess/internal/config"
+	"github.com/msvens/mchess/internal/repository"
+	"github.com/msvens/mchess/internal/service"
 )
 
 func TestRatingListHandler(t *testing.T) {
-	client := NewTestClient(t)
-	handler := handlers.NewRatingListHandler(client)
+	// Input validation happens before the service is used,
+	// so these tests don't need a database
+	handler := handlers.NewRatingListHandler(nil)
 
 	t.Run("GetFederationRatingList", func(t *testing.T) {
-		t.Run("ValidParams_ReturnsSuccess", func(t *testing.T) {
-			// Scenario 1: Correct input
-			// ratingtype: 1=Standard, 6=Rapid, 7=Blitz
-			// category: 0=All, 1=Juniors, etc.
-			rr := MakeRequest(t, handler.GetFederationRatingList, http.MethodGet,
-				"/ratinglist/federation/date/2024-01-01/ratingtype/1/category/0",
-				map[string]string{
-					"ratingdate": "2024-01-01",
-					"ratingtype": "1",
-					"category":   "0",
-				})
-
-			AssertStatus(t, rr, http.StatusOK)
-			AssertContentType(t, rr, "application/json")
-		})
-
 		t.Run("MalformedRatingType_Returns400", func(t *testing.T) {
 			// Scenario 4: Invalid input format
 			rr := MakeRequest(t, handler.GetFederationRatingList, http.MethodGet,
@@ -52,24 +42,22 @@ func TestRatingListHandler(t *testing.T) {
 
 			AssertStatus(t, rr, http.StatusBadRequest)
 		})
-	})
 
-	t.Run("GetDistrictRatingList", func(t *testing.T) {
-		t.Run("ValidParams_ReturnsSuccess", func(t *testing.T) {
-			// District 1 = Stockholm
-			rr := MakeRequest(t, handler.GetDistrictRatingList, http.MethodGet,
-				"/ratinglist/district/1/date/2024-01-01/ratingtype/1/category/0",
+		t.Run("MalformedRatingDate_Returns400", func(t *testing.T) {
+			rr := MakeRequest(t, handler.GetFederationRatingList, http.MethodGet,
+				"/ratinglist/federation/date/2024-13-45/ratingtype/1/category/0",
 				map[string]string{
-					"id":         "1",
-					"ratingdate": "2024-01-01",
+					"ratingdate": "2024-13-45",
 					"ratingtype": "1",
 					"category":   "0",
 				})
 
-			AssertStatus(t, rr, http.StatusOK)
-			AssertContentType(t, rr, "application/json")
+			AssertStatus(t, rr, http.StatusBadRequest)
+			AssertBodyContains(t, rr, "invalid rating date")
 		})
+	})
 
+	t.Run("GetDistrictRatingList", func(t *testing.T) {
 		t.Run("MalformedDistrictID_Returns400", func(t *testing.T) {
 			rr := MakeRequest(t, handler.GetDistrictRatingList, http.MethodGet,
 				"/ratinglist/district/abc/date/2024-01-01/ratingtype/1/category/0",
@@ -95,6 +83,20 @@ func TestRatingListHandler(t *testing.T) {
 
 			AssertStatus(t, rr, http.StatusBadRequest)
 		})
+
+		t.Run("MalformedRatingDate_Returns400", func(t *testing.T) {
+			rr := MakeRequest(t, handler.GetDistrictRatingList, http.MethodGet,
+				"/ratinglist/district/1/date/latest/ratingtype/1/category/0",
+				map[string]string{
+					"id":         "1",
+					"ratingdate": "latest",
+					"ratingtype": "1",
+					"category":   "0",
+				})
+
+			AssertStatus(t, rr, http.StatusBadRequest)
+			AssertBodyContains(t, rr, "invalid rating date")
+		})
 	})
 
 	t.Run("GetClubRatingList", func(t *testing.T) {
@@ -128,5 +130,96 @@ func TestRatingListHandler(t *testing.T) {
 
 			AssertStatus(t, rr, http.StatusBadRequest)
 		})
+
+		t.Run("MalformedRatingDate_Returns400", func(t *testing.T) {
+			rr := MakeRequest(t, handler.GetClubRatingList, http.MethodGet,
+				"/ratinglist/club/100/date/2024-1-1/ratingtype/1/category/0",
+				map[string]string{
+					"id":         "100",
+					"ratingdate": "2024-1-1",
+					"ratingtype": "1",
+					"category":   "0",
+				})
+
+			AssertStatus(t, rr, http.StatusBadRequest)
+			AssertBodyContains(t, rr, "invalid rating date")
+		})
+	})
+}
+
+// TestRatingListHandler_Caching tests snapshot behavior (requires database)
+func TestRatingListHandler_Caching(t *testing.T) {
+	SetupTestDB(t)
+	ClearTestDB(t)
+
+	database := NewTestDB(t)
+	defer database.Close()
+
+	client := NewTestClient(t)
+	repo := repository.NewRatingListRepository(database.DB)
+	cfg := &config.Config{
+		Cache: config.CacheConfig{TTL: 24 * time.Hour},
+	}
+	svc := service.NewRatingListService(repo, client, nil, cfg)
+	handler := handlers.NewRatingListHandler(svc)
+
+	t.Run("Federation_ValidParams_ReturnsSuccess", func(t *testing.T) {
+		// Scenario 1: Correct input
+		// ratingtype: 1=Standard, 6=Rapid, 7=Blitz
+		// category: 0=All, 1=Juniors, etc.
+		rr := MakeRequest(t, handler.GetFederationRatingList, http.MethodGet,
+			"/ratinglist/federation/date/2024-01-01/ratingtype/1/category/0",
+			map[string]string{
+				"ratingdate": "2024-01-01",
+				"ratingtype": "1",
+				"category":   "0",
+			})
+
+		AssertStatus(t, rr, http.StatusOK)
+		AssertContentType(t, rr, "application/json")
+	})
+
+	t.Run("District_ValidParams_ReturnsSuccess", func(t *testing.T) {
+		// District 1 = Stockholm
+		rr := MakeRequest(t, handler.GetDistrictRatingList, http.MethodGet,
+			"/ratinglist/district/1/date/2024-01-01/ratingtype/1/category/0",
+			map[string]string{
+				"id":         "1",
+				"ratingdate": "2024-01-01",
+				"ratingtype": "1",
+				"category":   "0",
+			})
+
+		AssertStatus(t, rr, http.StatusOK)
+		AssertContentType(t, rr, "application/json")
+	})
+
+	t.Run("PastMonth_NeverExpires", func(t *testing.T) {
+		// Both lists above are for a past month
+		var expiresAt sql.NullTime
+		err := database.QueryRow(
+			`SELECT expires_at FROM rating_list_snapshot WHERE scope = 'district' AND scope_id = 1`,
+		).Scan(&expiresAt)
+		if err != nil {
+			t.Fatalf("Failed to query snapshot: %v", err)
+		}
+		if expiresAt.Valid {
+			t.Errorf("expires_at: got %v, want NULL", expiresAt.Time)
+		}
+	})
+
+	t.Run("CachedResponse_MatchesUpstream", func(t *testing.T) {
+		// Served from the snapshot, with every upstream field kept
+		rr := MakeRequest(t, handler.GetDistrictRatingList, http.MethodGet,
+			"/ratinglist/district/1/date/2024-01-01/ratingtype/1/category/0",
+			map[string]string{
+				"id":         "1",
+				"ratingdate": "2024-01-01",
+				"ratingtype": "1",
+				"category":   "0",
+			})
+
+		AssertStatus(t, rr, http.StatusOK)
+		AssertUpstreamJSON(t, rr, client, "/ratinglist/district/1/date/2024-01-01/ratingtype/1/category/0")
 	})
 }
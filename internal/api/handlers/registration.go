@@ -28,27 +28,30 @@ func NewRegistrationHandler(client *upstream.Client) *RegistrationHandler {
 // @Param clubid path int true "Club ID"
 // @Success 200 {object} object
 // @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 504 {object} ErrorResponse
 // @Router /tournamentteamregistration/tournament/{id}/club/{clubid} [get]
 func (h *RegistrationHandler) GetTeamRegistration(w http.ResponseWriter, r *http.Request) {
 	tournamentIDStr := chi.URLParam(r, "id")
 	tournamentID, err := strconv.Atoi(tournamentIDStr)
 	if err != nil {
-		WriteError(w, http.StatusBadRequest, "invalid tournament id")
+		WriteBadRequest(w, "invalid tournament id")
 		return
 	}
 
 	clubIDStr := chi.URLParam(r, "clubid")
 	clubID, err := strconv.Atoi(clubIDStr)
 	if err != nil {
-		WriteError(w, http.StatusBadRequest, "invalid club id")
+		WriteBadRequest(w, "invalid club id")
 		return
 	}
 
 	path := fmt.Sprintf("/tournamentteamregistration/tournament/%d/club/%d", tournamentID, clubID)
 	data, err := h.client.GetRaw(r.Context(), path)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteError(w, err)
 		return
 	}
 	WriteRawJSON(w, http.StatusOK, data)
@@ -14,8 +14,13 @@ func TestRegistrationHandler(t *testing.T) {
 	t.Run("GetTeamRegistration", func(t *testing.T) {
 		t.Run("ValidIDs_ReturnsSuccess", func(t *testing.T) {
 			// Scenario 1: Correct input - existing tournament and club IDs
-			// TODO: Find valid tournament and club IDs to test with
-			t.Skip("TODO: Implement with valid tournament and club IDs")
+			rr := MakeRequest(t, handler.GetTeamRegistration, http.MethodGet,
+				"/tournamentteamregistration/tournament/6100/club/101",
+				map[string]string{"id": "6100", "clubid": "101"})
+
+			AssertStatus(t, rr, http.StatusOK)
+			AssertContentType(t, rr, "application/json")
+			AssertBodyContains(t, rr, "Stockholms Schacksällskap")
 		})
 
 		t.Run("InvalidTournamentID_ReturnsError", func(t *testing.T) {
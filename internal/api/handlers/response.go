@@ -1,8 +1,13 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+
+	"github.com/msvens/mchess/internal/spec"
+	"github.com/msvens/mchess/internal/upstream"
 )
 
 // WriteJSON writes a JSON response by encoding the data
@@ -19,8 +24,45 @@ func WriteRawJSON(w http.ResponseWriter, status int, data []byte) {
 	w.Write(data)
 }
 
-// WriteError writes an error response
-func WriteError(w http.ResponseWriter, status int, message string) {
+// WriteError writes an error response. The status code is chosen from the
+// error: 404 and 400 when upstream rejected the request, 504 when upstream
+// timed out, 502 for other upstream failures and 500 for anything else.
+// Responses that failed spec validation list the problems in details.
+func WriteError(w http.ResponseWriter, err error) {
+	status := errorStatus(err)
+	var validationErr *spec.ValidationError
+	if errors.As(err, &validationErr) {
+		WriteJSON(w, status, ErrorResponse{Error: err.Error(), Code: status, Details: validationErr.Problems})
+		return
+	}
+	writeError(w, status, err.Error())
+}
+
+// WriteBadRequest writes a 400 response for invalid request parameters
+func WriteBadRequest(w http.ResponseWriter, message string) {
+	writeError(w, http.StatusBadRequest, message)
+}
+
+// errorStatus maps an error to an HTTP status code
+func errorStatus(err error) int {
+	var upstreamErr *upstream.Error
+	switch {
+	// Check timeouts first, they are also upstream.ErrUnavailable
+	case errors.Is(err, upstream.ErrTimeout), errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, upstream.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, upstream.ErrBadRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, upstream.ErrUnavailable), errors.Is(err, upstream.ErrInvalidResponse), errors.As(err, &upstreamErr):
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeError writes an error response with the given status
+func writeError(w http.ResponseWriter, status int, message string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(map[string]interface{}{
@@ -0,0 +1,76 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/msvens/mchess/internal/api/handlers"
+	"github.com/msvens/mchess/internal/spec"
+	"github.com/msvens/mchess/internal/upstream"
+)
+
+func TestWriteError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"UpstreamNotFound_Returns404", &upstream.Error{StatusCode: http.StatusNotFound}, http.StatusNotFound},
+		{"UpstreamBadRequest_Returns400", &upstream.Error{StatusCode: http.StatusBadRequest}, http.StatusBadRequest},
+		{"UpstreamServerError_Returns502", &upstream.Error{StatusCode: http.StatusServiceUnavailable}, http.StatusBadGateway},
+		{"UpstreamForbidden_Returns502", &upstream.Error{StatusCode: http.StatusForbidden}, http.StatusBadGateway},
+		{"UpstreamTimeout_Returns504", &upstream.Error{StatusCode: http.StatusGatewayTimeout}, http.StatusGatewayTimeout},
+		{"CircuitOpen_Returns502", upstream.ErrCircuitOpen, http.StatusBadGateway},
+		{"InvalidResponse_Returns502", fmt.Errorf("decode response: %w", upstream.ErrInvalidResponse), http.StatusBadGateway},
+		{"DeadlineExceeded_Returns504", fmt.Errorf("rate limit wait: %w", context.DeadlineExceeded), http.StatusGatewayTimeout},
+		{"WrappedNotFound_Returns404", fmt.Errorf("upstream fetch: %w", &upstream.Error{StatusCode: http.StatusNotFound}), http.StatusNotFound},
+		{"InvalidSpec_Returns502", fmt.Errorf("validate response: %w: %w", upstream.ErrInvalidResponse, &spec.ValidationError{Endpoint: "/organisation/districts", Problems: []string{"$: want array, got object"}}), http.StatusBadGateway},
+		{"OtherError_Returns500", errors.New("query player cache: connection refused"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			handlers.WriteError(rr, tt.err)
+
+			AssertStatus(t, rr, tt.want)
+			AssertContentType(t, rr, "application/json")
+			AssertBodyContains(t, rr, `"error"`)
+		})
+	}
+
+	t.Run("InvalidSpec_ListsDetails", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handlers.WriteError(rr, fmt.Errorf("validate response: %w: %w", upstream.ErrInvalidResponse,
+			&spec.ValidationError{Endpoint: "/organisation/districts", Problems: []string{"$: want array, got object"}}))
+
+		AssertStatus(t, rr, http.StatusBadGateway)
+		AssertBodyContains(t, rr, `"details":["$: want array, got object"]`)
+	})
+
+	t.Run("RateLimitPastDeadline_Returns504", func(t *testing.T) {
+		// The limiter fails without a context error when the wait would
+		// outlast the deadline
+		client := NewTestClient(t)
+		limiter := rate.NewLimiter(rate.Every(time.Hour), 1)
+		limiter.Allow()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_, err := client.GetRaw(upstream.WithLimiter(ctx, limiter), "/organisation/districts")
+		if errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("GetRaw error %v is a context error", err)
+		}
+
+		rr := httptest.NewRecorder()
+		handlers.WriteError(rr, err)
+
+		AssertStatus(t, rr, http.StatusGatewayTimeout)
+	})
+}
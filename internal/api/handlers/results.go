@@ -6,46 +6,76 @@ import (
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/msvens/mchess/internal/service"
 	"github.com/msvens/mchess/internal/upstream"
 )
 
-// ResultsHandler handles tournament results requests (pass-through)
+// ResultsHandler handles tournament results requests
 type ResultsHandler struct {
-	client *upstream.Client
+	service *service.ResultsService
+	client  *upstream.Client // For pass-through when no caching needed
 }
 
 // NewResultsHandler creates a new results handler
-func NewResultsHandler(client *upstream.Client) *ResultsHandler {
-	return &ResultsHandler{client: client}
+func NewResultsHandler(service *service.ResultsService, client *upstream.Client) *ResultsHandler {
+	return &ResultsHandler{service: service, client: client}
 }
 
 // GetResultTable returns individual tournament table
 // @Summary Get tournament table
-// @Description Get individual tournament standings by group ID
+// @Description Get individual tournament standings by group ID (cached)
 // @Tags tournamentresults
 // @Produce json
 // @Param id path int true "Group ID"
-// @Success 200 {array} object
+// @Success 200 {array} model.TournamentEndResult
 // @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 504 {object} ErrorResponse
 // @Router /tournamentresults/table/id/{id} [get]
 func (h *ResultsHandler) GetResultTable(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		WriteError(w, http.StatusBadRequest, "invalid group id")
+		WriteBadRequest(w, "invalid group id")
 		return
 	}
 
-	path := fmt.Sprintf("/tournamentresults/table/id/%d", id)
-	data, err := h.client.GetRaw(r.Context(), path)
+	data, err := h.service.GetResultTableRaw(r.Context(), id)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteError(w, err)
 		return
 	}
+
 	WriteRawJSON(w, http.StatusOK, data)
 }
 
+// GetResultTables handles GET /tournamentresults/table/batch?ids=1,2,3
+// @Summary Batch fetch multiple tournament tables
+// @Description Fetch individual standings for multiple groups in a single request by providing comma-separated group IDs. Maximum 100 IDs per request (batch.maxSize). All lookups are cached. If the request deadline is near, partial results are returned with errors for the IDs that were not fetched. (mchess extension)
+// @Tags tournamentresults
+// @Produce json
+// @Param ids query string true "Comma-separated group IDs (max 100)" example:"2001,2002,2003"
+// @Success 200 {object} model.ResultTablesResponse "Result tables with any errors for failed lookups"
+// @Failure 400 {object} ErrorResponse "Invalid request (missing/invalid IDs, too many IDs)"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /tournamentresults/table/batch [get]
+func (h *ResultsHandler) GetResultTables(w http.ResponseWriter, r *http.Request) {
+	ids, ok := batchIDs(w, r, h.service.MaxBatchSize())
+	if !ok {
+		return
+	}
+
+	response, err := h.service.GetResultTables(r.Context(), ids)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, response)
+}
+
 // GetMemberTableResults returns member's tournament results
 // @Summary Get member tournament results
 // @Description Get all tournament results for a specific member
@@ -54,20 +84,23 @@ func (h *ResultsHandler) GetResultTable(w http.ResponseWriter, r *http.Request)
 // @Param id path int true "Member ID"
 // @Success 200 {array} object
 // @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 504 {object} ErrorResponse
 // @Router /tournamentresults/table/memberid/{id} [get]
 func (h *ResultsHandler) GetMemberTableResults(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		WriteError(w, http.StatusBadRequest, "invalid member id")
+		WriteBadRequest(w, "invalid member id")
 		return
 	}
 
 	path := fmt.Sprintf("/tournamentresults/table/memberid/%d", id)
 	data, err := h.client.GetRaw(r.Context(), path)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteError(w, err)
 		return
 	}
 	WriteRawJSON(w, http.StatusOK, data)
@@ -75,82 +108,91 @@ func (h *ResultsHandler) GetMemberTableResults(w http.ResponseWriter, r *http.Re
 
 // GetRoundResults returns round results
 // @Summary Get round results
-// @Description Get round-by-round results for a group
+// @Description Get round-by-round results for a group (cached)
 // @Tags tournamentresults
 // @Produce json
 // @Param id path int true "Group ID"
-// @Success 200 {array} object
+// @Success 200 {array} model.TournamentRoundResult
 // @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 504 {object} ErrorResponse
 // @Router /tournamentresults/roundresults/id/{id} [get]
 func (h *ResultsHandler) GetRoundResults(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		WriteError(w, http.StatusBadRequest, "invalid group id")
+		WriteBadRequest(w, "invalid group id")
 		return
 	}
 
-	path := fmt.Sprintf("/tournamentresults/roundresults/id/%d", id)
-	data, err := h.client.GetRaw(r.Context(), path)
+	data, err := h.service.GetRoundResultsRaw(r.Context(), id)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteError(w, err)
 		return
 	}
+
 	WriteRawJSON(w, http.StatusOK, data)
 }
 
 // GetTeamResultTable returns team tournament table
 // @Summary Get team tournament table
-// @Description Get team tournament standings by group ID
+// @Description Get team tournament standings by group ID (cached)
 // @Tags tournamentresults
 // @Produce json
 // @Param id path int true "Group ID"
-// @Success 200 {array} object
+// @Success 200 {array} model.TeamTournamentEndResult
 // @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 504 {object} ErrorResponse
 // @Router /tournamentresults/team/table/id/{id} [get]
 func (h *ResultsHandler) GetTeamResultTable(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		WriteError(w, http.StatusBadRequest, "invalid group id")
+		WriteBadRequest(w, "invalid group id")
 		return
 	}
 
-	path := fmt.Sprintf("/tournamentresults/team/table/id/%d", id)
-	data, err := h.client.GetRaw(r.Context(), path)
+	data, err := h.service.GetTeamResultTableRaw(r.Context(), id)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteError(w, err)
 		return
 	}
+
 	WriteRawJSON(w, http.StatusOK, data)
 }
 
 // GetTeamRoundResults returns team round results
 // @Summary Get team round results
-// @Description Get team round-by-round results for a group
+// @Description Get team round-by-round results for a group (cached)
 // @Tags tournamentresults
 // @Produce json
 // @Param id path int true "Group ID"
-// @Success 200 {array} object
+// @Success 200 {array} model.TournamentRoundResult
 // @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 504 {object} ErrorResponse
 // @Router /tournamentresults/team/roundresults/id/{id} [get]
 func (h *ResultsHandler) GetTeamRoundResults(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		WriteError(w, http.StatusBadRequest, "invalid group id")
+		WriteBadRequest(w, "invalid group id")
 		return
 	}
 
-	path := fmt.Sprintf("/tournamentresults/team/roundresults/id/%d", id)
-	data, err := h.client.GetRaw(r.Context(), path)
+	data, err := h.service.GetTeamRoundResultsRaw(r.Context(), id)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteError(w, err)
 		return
 	}
+
 	WriteRawJSON(w, http.StatusOK, data)
 }
 
@@ -163,27 +205,30 @@ func (h *ResultsHandler) GetTeamRoundResults(w http.ResponseWriter, r *http.Requ
 // @Param memberid path int true "Member ID"
 // @Success 200 {array} object
 // @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 504 {object} ErrorResponse
 // @Router /tournamentresults/team/roundresults/id/{id}/memberid/{memberid} [get]
 func (h *ResultsHandler) GetTeamRoundResultsForMember(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		WriteError(w, http.StatusBadRequest, "invalid group id")
+		WriteBadRequest(w, "invalid group id")
 		return
 	}
 
 	memberIDStr := chi.URLParam(r, "memberid")
 	memberID, err := strconv.Atoi(memberIDStr)
 	if err != nil {
-		WriteError(w, http.StatusBadRequest, "invalid member id")
+		WriteBadRequest(w, "invalid member id")
 		return
 	}
 
 	path := fmt.Sprintf("/tournamentresults/team/roundresults/id/%d/memberid/%d", id, memberID)
 	data, err := h.client.GetRaw(r.Context(), path)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteError(w, err)
 		return
 	}
 	WriteRawJSON(w, http.StatusOK, data)
@@ -197,20 +242,23 @@ func (h *ResultsHandler) GetTeamRoundResultsForMember(w http.ResponseWriter, r *
 // @Param id path int true "Member ID"
 // @Success 200 {array} object
 // @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 504 {object} ErrorResponse
 // @Router /tournamentresults/game/memberid/{id} [get]
 func (h *ResultsHandler) GetMemberGames(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		WriteError(w, http.StatusBadRequest, "invalid member id")
+		WriteBadRequest(w, "invalid member id")
 		return
 	}
 
 	path := fmt.Sprintf("/tournamentresults/game/memberid/%d", id)
 	data, err := h.client.GetRaw(r.Context(), path)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteError(w, err)
 		return
 	}
 	WriteRawJSON(w, http.StatusOK, data)
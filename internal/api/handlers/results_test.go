@@ -3,13 +3,21 @@ package handlers_test
 import (
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/msvens/mchess/internal/api/handlers"
+	"github.com/msvens/mchess/internal/config"
+	"github.com/msvens/mchess/internal/model"
+	"github.com/msvens/mchess/internal/repository"
+	"github.com/msvens/mchess/internal/service"
+	"github.com/msvens/mchess/internal/upstream"
 )
 
 func TestResultsHandler(t *testing.T) {
 	client := NewTestClient(t)
-	handler := handlers.NewResultsHandler(client)
+
+	// For simple tests that don't need caching, create handler without service
+	handler := handlers.NewResultsHandler(nil, client)
 
 	t.Run("GetResultTable", func(t *testing.T) {
 		t.Run("ValidGroupID_ReturnsSuccess", func(t *testing.T) {
@@ -35,8 +43,13 @@ func TestResultsHandler(t *testing.T) {
 
 	t.Run("GetMemberTableResults", func(t *testing.T) {
 		t.Run("ValidMemberID_ReturnsSuccess", func(t *testing.T) {
-			// TODO: Find a valid member ID to test with
-			t.Skip("TODO: Implement with valid member ID")
+			rr := MakeRequest(t, handler.GetMemberTableResults, http.MethodGet,
+				"/tournamentresults/table/memberid/500001",
+				map[string]string{"id": "500001"})
+
+			AssertStatus(t, rr, http.StatusOK)
+			AssertContentType(t, rr, "application/json")
+			AssertBodyContains(t, rr, "Andersson")
 		})
 
 		t.Run("MalformedMemberID_Returns400", func(t *testing.T) {
@@ -114,7 +127,13 @@ func TestResultsHandler(t *testing.T) {
 
 	t.Run("GetMemberGames", func(t *testing.T) {
 		t.Run("ValidMemberID_ReturnsSuccess", func(t *testing.T) {
-			t.Skip("TODO: Implement with valid member ID")
+			rr := MakeRequest(t, handler.GetMemberGames, http.MethodGet,
+				"/tournamentresults/game/memberid/500001",
+				map[string]string{"id": "500001"})
+
+			AssertStatus(t, rr, http.StatusOK)
+			AssertContentType(t, rr, "application/json")
+			AssertBodyContains(t, rr, "500001")
 		})
 
 		t.Run("MalformedMemberID_Returns400", func(t *testing.T) {
@@ -126,3 +145,36 @@ func TestResultsHandler(t *testing.T) {
 		})
 	})
 }
+
+// TestResultsHandler_Batch tests partial batch results (requires database)
+func TestResultsHandler_Batch(t *testing.T) {
+	SetupTestDB(t)
+	ClearTestDB(t)
+
+	database := NewTestDB(t)
+	defer database.Close()
+
+	server := NewFakeUpstream(t)
+	client := upstream.NewClient(server.BaseURL(), 5*time.Second, 100)
+	cfg := &config.Config{
+		Cache: config.CacheConfig{TournamentTTL: time.Hour, ResultsTTL: time.Hour},
+	}
+	tournaments := service.NewTournamentService(repository.NewTournamentRepository(database.DB), client, nil, cfg)
+	svc := service.NewResultsService(repository.NewResultsRepository(database.DB), client, tournaments, nil, cfg)
+	handler := handlers.NewResultsHandler(svc, client)
+
+	t.Run("UnknownID_PartialResults", func(t *testing.T) {
+		// The fake serves empty results for any group, so make 99999 unknown
+		server.Fail("/tournamentresults/table/id/99999", http.StatusNotFound, 0)
+
+		rr := MakeRequestWithQuery(t, handler.GetResultTables, http.MethodGet,
+			"/tournamentresults/table/batch?ids=17001,99999", nil)
+
+		AssertStatus(t, rr, http.StatusOK)
+		var response model.ResultTablesResponse
+		DecodeBatchResponse(t, rr, &response, 99999)
+		if len(response.Tables) != 1 || response.Tables[0].GroupID != 17001 || len(response.Tables[0].Results) == 0 {
+			t.Errorf("Tables: got %v, want group 17001 with results", response.Tables)
+		}
+	})
+}
@@ -7,97 +7,133 @@ import (
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/msvens/mchess/internal/service"
 	"github.com/msvens/mchess/internal/upstream"
 )
 
-// TournamentHandler handles tournament-related requests (pass-through)
+// TournamentHandler handles tournament-related requests
 type TournamentHandler struct {
-	client *upstream.Client
+	service *service.TournamentService
+	client  *upstream.Client // For pass-through when no caching needed
 }
 
 // NewTournamentHandler creates a new tournament handler
-func NewTournamentHandler(client *upstream.Client) *TournamentHandler {
-	return &TournamentHandler{client: client}
+func NewTournamentHandler(service *service.TournamentService, client *upstream.Client) *TournamentHandler {
+	return &TournamentHandler{service: service, client: client}
 }
 
 // GetTournament returns tournament by ID
 // @Summary Get tournament by ID
-// @Description Get tournament information by tournament ID
+// @Description Get tournament information by tournament ID (cached)
 // @Tags tournament
 // @Produce json
 // @Param id path int true "Tournament ID"
-// @Success 200 {object} object
+// @Success 200 {object} model.Tournament
 // @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 504 {object} ErrorResponse
 // @Router /tournament/tournament/id/{id} [get]
 func (h *TournamentHandler) GetTournament(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		WriteError(w, http.StatusBadRequest, "invalid tournament id")
+		WriteBadRequest(w, "invalid tournament id")
 		return
 	}
 
-	path := fmt.Sprintf("/tournament/tournament/id/%d", id)
-	data, err := h.client.GetRaw(r.Context(), path)
+	data, err := h.service.GetTournamentRaw(r.Context(), id)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteError(w, err)
 		return
 	}
+
 	WriteRawJSON(w, http.StatusOK, data)
 }
 
+// GetTournaments handles GET /tournament/tournament/batch?ids=1,2,3
+// @Summary Batch fetch multiple tournaments
+// @Description Fetch multiple tournaments in a single request by providing comma-separated tournament IDs. Maximum 100 IDs per request (batch.maxSize). All lookups are cached. If the request deadline is near, partial results are returned with errors for the IDs that were not fetched. (mchess extension)
+// @Tags tournament
+// @Produce json
+// @Param ids query string true "Comma-separated tournament IDs (max 100)" example:"1001,1002,1003"
+// @Success 200 {object} model.TournamentsResponse "Tournaments with any errors for failed lookups"
+// @Failure 400 {object} ErrorResponse "Invalid request (missing/invalid IDs, too many IDs)"
+// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Router /tournament/tournament/batch [get]
+func (h *TournamentHandler) GetTournaments(w http.ResponseWriter, r *http.Request) {
+	ids, ok := batchIDs(w, r, h.service.MaxBatchSize())
+	if !ok {
+		return
+	}
+
+	response, err := h.service.GetTournaments(r.Context(), ids)
+	if err != nil {
+		WriteError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, response)
+}
+
 // GetTournamentFromGroup returns tournament by group ID
 // @Summary Get tournament by group ID
-// @Description Get tournament information from a group ID
+// @Description Get tournament information from a group ID (cached)
 // @Tags tournament
 // @Produce json
 // @Param id path int true "Group ID"
-// @Success 200 {object} object
+// @Success 200 {object} model.Tournament
 // @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 504 {object} ErrorResponse
 // @Router /tournament/group/id/{id} [get]
 func (h *TournamentHandler) GetTournamentFromGroup(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		WriteError(w, http.StatusBadRequest, "invalid group id")
+		WriteBadRequest(w, "invalid group id")
 		return
 	}
 
-	path := fmt.Sprintf("/tournament/group/id/%d", id)
-	data, err := h.client.GetRaw(r.Context(), path)
+	data, err := h.service.GetTournamentFromGroupRaw(r.Context(), id)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteError(w, err)
 		return
 	}
+
 	WriteRawJSON(w, http.StatusOK, data)
 }
 
 // GetTournamentFromClass returns tournament by class ID
 // @Summary Get tournament by class ID
-// @Description Get tournament information from a class/division ID
+// @Description Get tournament information from a class/division ID (cached)
 // @Tags tournament
 // @Produce json
 // @Param id path int true "Class ID"
-// @Success 200 {object} object
+// @Success 200 {object} model.Tournament
 // @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 504 {object} ErrorResponse
 // @Router /tournament/class/id/{id} [get]
 func (h *TournamentHandler) GetTournamentFromClass(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
 	id, err := strconv.Atoi(idStr)
 	if err != nil {
-		WriteError(w, http.StatusBadRequest, "invalid class id")
+		WriteBadRequest(w, "invalid class id")
 		return
 	}
 
-	path := fmt.Sprintf("/tournament/class/id/%d", id)
-	data, err := h.client.GetRaw(r.Context(), path)
+	data, err := h.service.GetTournamentFromClassRaw(r.Context(), id)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteError(w, err)
 		return
 	}
+
 	WriteRawJSON(w, http.StatusOK, data)
 }
 
@@ -108,7 +144,10 @@ func (h *TournamentHandler) GetTournamentFromClass(w http.ResponseWriter, r *htt
 // @Produce json
 // @Param searchWord path string true "Search word"
 // @Success 200 {array} object
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 504 {object} ErrorResponse
 // @Router /tournament/group/search/{searchWord} [get]
 func (h *TournamentHandler) SearchTournamentGroups(w http.ResponseWriter, r *http.Request) {
 	searchWord := chi.URLParam(r, "searchWord")
@@ -116,7 +155,7 @@ func (h *TournamentHandler) SearchTournamentGroups(w http.ResponseWriter, r *htt
 	path := fmt.Sprintf("/tournament/group/search/%s", url.PathEscape(searchWord))
 	data, err := h.client.GetRaw(r.Context(), path)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteError(w, err)
 		return
 	}
 	WriteRawJSON(w, http.StatusOK, data)
@@ -128,12 +167,15 @@ func (h *TournamentHandler) SearchTournamentGroups(w http.ResponseWriter, r *htt
 // @Tags tournament
 // @Produce json
 // @Success 200 {array} object
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 504 {object} ErrorResponse
 // @Router /tournament/group/coming [get]
 func (h *TournamentHandler) GetComingTournaments(w http.ResponseWriter, r *http.Request) {
 	data, err := h.client.GetRaw(r.Context(), "/tournament/group/coming")
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteError(w, err)
 		return
 	}
 	WriteRawJSON(w, http.StatusOK, data)
@@ -147,20 +189,23 @@ func (h *TournamentHandler) GetComingTournaments(w http.ResponseWriter, r *http.
 // @Param districtid path int true "District ID"
 // @Success 200 {array} object
 // @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 504 {object} ErrorResponse
 // @Router /tournament/group/coming/{districtid} [get]
 func (h *TournamentHandler) GetComingTournamentsByDistrict(w http.ResponseWriter, r *http.Request) {
 	districtIDStr := chi.URLParam(r, "districtid")
 	districtID, err := strconv.Atoi(districtIDStr)
 	if err != nil {
-		WriteError(w, http.StatusBadRequest, "invalid district id")
+		WriteBadRequest(w, "invalid district id")
 		return
 	}
 
 	path := fmt.Sprintf("/tournament/group/coming/%d", districtID)
 	data, err := h.client.GetRaw(r.Context(), path)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteError(w, err)
 		return
 	}
 	WriteRawJSON(w, http.StatusOK, data)
@@ -174,7 +219,10 @@ func (h *TournamentHandler) GetComingTournamentsByDistrict(w http.ResponseWriter
 // @Param startdate path string true "Start date (ISO 8601)"
 // @Param enddate path string true "End date (ISO 8601)"
 // @Success 200 {array} object
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 504 {object} ErrorResponse
 // @Router /tournament/tournament/updated/{startdate}/{enddate} [get]
 func (h *TournamentHandler) SearchUpdatedTournaments(w http.ResponseWriter, r *http.Request) {
 	startDate := chi.URLParam(r, "startdate")
@@ -183,7 +231,7 @@ func (h *TournamentHandler) SearchUpdatedTournaments(w http.ResponseWriter, r *h
 	path := fmt.Sprintf("/tournament/tournament/updated/%s/%s", startDate, endDate)
 	data, err := h.client.GetRaw(r.Context(), path)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteError(w, err)
 		return
 	}
 	WriteRawJSON(w, http.StatusOK, data)
@@ -199,7 +247,10 @@ func (h *TournamentHandler) SearchUpdatedTournaments(w http.ResponseWriter, r *h
 // @Param districtid path int true "District ID"
 // @Success 200 {array} object
 // @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 504 {object} ErrorResponse
 // @Router /tournament/tournament/updated/{startdate}/{enddate}/{districtid} [get]
 func (h *TournamentHandler) SearchUpdatedTournamentsByDistrict(w http.ResponseWriter, r *http.Request) {
 	startDate := chi.URLParam(r, "startdate")
@@ -208,14 +259,14 @@ func (h *TournamentHandler) SearchUpdatedTournamentsByDistrict(w http.ResponseWr
 
 	districtID, err := strconv.Atoi(districtIDStr)
 	if err != nil {
-		WriteError(w, http.StatusBadRequest, "invalid district id")
+		WriteBadRequest(w, "invalid district id")
 		return
 	}
 
 	path := fmt.Sprintf("/tournament/tournament/updated/%s/%s/%d", startDate, endDate, districtID)
 	data, err := h.client.GetRaw(r.Context(), path)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteError(w, err)
 		return
 	}
 	WriteRawJSON(w, http.StatusOK, data)
@@ -229,7 +280,10 @@ func (h *TournamentHandler) SearchUpdatedTournamentsByDistrict(w http.ResponseWr
 // @Param startdate path string true "Start date (ISO 8601)"
 // @Param enddate path string true "End date (ISO 8601)"
 // @Success 200 {array} object
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 504 {object} ErrorResponse
 // @Router /tournament/group/updated/{startdate}/{enddate} [get]
 func (h *TournamentHandler) SearchUpdatedGroups(w http.ResponseWriter, r *http.Request) {
 	startDate := chi.URLParam(r, "startdate")
@@ -238,7 +292,7 @@ func (h *TournamentHandler) SearchUpdatedGroups(w http.ResponseWriter, r *http.R
 	path := fmt.Sprintf("/tournament/group/updated/%s/%s", startDate, endDate)
 	data, err := h.client.GetRaw(r.Context(), path)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteError(w, err)
 		return
 	}
 	WriteRawJSON(w, http.StatusOK, data)
@@ -254,7 +308,10 @@ func (h *TournamentHandler) SearchUpdatedGroups(w http.ResponseWriter, r *http.R
 // @Param districtid path int true "District ID"
 // @Success 200 {array} object
 // @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Failure 504 {object} ErrorResponse
 // @Router /tournament/group/updated/{startdate}/{enddate}/{districtid} [get]
 func (h *TournamentHandler) SearchUpdatedGroupsByDistrict(w http.ResponseWriter, r *http.Request) {
 	startDate := chi.URLParam(r, "startdate")
@@ -263,14 +320,14 @@ func (h *TournamentHandler) SearchUpdatedGroupsByDistrict(w http.ResponseWriter,
 
 	districtID, err := strconv.Atoi(districtIDStr)
 	if err != nil {
-		WriteError(w, http.StatusBadRequest, "invalid district id")
+		WriteBadRequest(w, "invalid district id")
 		return
 	}
 
 	path := fmt.Sprintf("/tournament/group/updated/%s/%s/%d", startDate, endDate, districtID)
 	data, err := h.client.GetRaw(r.Context(), path)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, err.Error())
+		WriteError(w, err)
 		return
 	}
 	WriteRawJSON(w, http.StatusOK, data)
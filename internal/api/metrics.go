@@ -0,0 +1,50 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/msvens/mchess/internal/metrics"
+)
+
+var (
+	httpRequests = metrics.NewCounterVec(
+		"mchess_http_requests_total",
+		"Total number of HTTP requests by route pattern and status code.",
+		"method", "route", "status",
+	)
+	httpRequestDuration = metrics.NewHistogramVec(
+		"mchess_http_request_duration_seconds",
+		"HTTP request latency by route pattern.",
+		nil,
+		"method", "route",
+	)
+)
+
+// metricsMiddleware records request counts and latencies per chi route pattern.
+// Route patterns are used instead of paths to keep label cardinality bounded.
+func metricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r)
+
+		// The pattern is only complete once routing has finished
+		route := "unmatched"
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		httpRequests.Inc(r.Method, route, strconv.Itoa(status))
+		httpRequestDuration.Observe(time.Since(start).Seconds(), r.Method, route)
+	})
+}
@@ -17,16 +17,17 @@ import (
 
 	"github.com/msvens/mchess/internal/api/handlers"
 	"github.com/msvens/mchess/internal/config"
-	"github.com/msvens/mchess/internal/db"
-	"github.com/msvens/mchess/internal/repository"
-	"github.com/msvens/mchess/internal/service"
-	"github.com/msvens/mchess/internal/upstream"
+	"github.com/msvens/mchess/internal/metrics"
 )
 
 // @title mchess API
 // @version 1.0
 // @description A caching proxy for the Swedish Chess Federation (schack.se) API
 // @BasePath /api
+// @securityDefinitions.apikey AdminToken
+// @in header
+// @name Authorization
+// @description Admin token as "Bearer <admin.token>"
 
 // Server represents the API server
 type Server struct {
@@ -38,37 +39,33 @@ type Server struct {
 	tournamentHandler   *handlers.TournamentHandler
 	resultsHandler      *handlers.ResultsHandler
 	registrationHandler *handlers.RegistrationHandler
-	db                  *db.DB
+	adminHandler        *handlers.AdminHandler
+	services            *services
+	stopBackground      context.CancelFunc // Stops background jobs
 }
 
 // NewServer creates a new API server
 func NewServer(cfg *config.Config) (*Server, error) {
-	// Initialize database
-	database, err := db.New(cfg.DBConnectionString())
+	svc, err := newServices(cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	// Initialize upstream client
-	upstreamClient := upstream.NewClient(
-		cfg.Upstream.BaseURL,
-		cfg.Upstream.Timeout,
-		cfg.Upstream.RateLimit,
-	)
-
-	// Initialize repository
-	playerRepo := repository.NewPlayerRepository(database.DB)
-
-	// Initialize service
-	playerService := service.NewPlayerService(playerRepo, upstreamClient, cfg)
-
 	// Initialize handlers
-	playerHandler := handlers.NewPlayerHandler(playerService, upstreamClient)
-	organisationHandler := handlers.NewOrganisationHandler(upstreamClient)
-	ratingListHandler := handlers.NewRatingListHandler(upstreamClient)
-	tournamentHandler := handlers.NewTournamentHandler(upstreamClient)
-	resultsHandler := handlers.NewResultsHandler(upstreamClient)
-	registrationHandler := handlers.NewRegistrationHandler(upstreamClient)
+	playerHandler := handlers.NewPlayerHandler(svc.players, svc.upstream)
+	organisationHandler := handlers.NewOrganisationHandler(svc.organisations, svc.upstream)
+	ratingListHandler := handlers.NewRatingListHandler(svc.ratingLists)
+	tournamentHandler := handlers.NewTournamentHandler(svc.tournaments, svc.upstream)
+	resultsHandler := handlers.NewResultsHandler(svc.results, svc.upstream)
+	registrationHandler := handlers.NewRegistrationHandler(svc.upstream)
+	adminHandler := handlers.NewAdminHandler(svc.stats, svc.upstream, svc.cacheAdmin, svc.players)
+
+	// Start background jobs
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	go svc.organisations.RunRefresher(backgroundCtx)
+	go svc.stats.RunFlusher(backgroundCtx)
+	go svc.warmer.Run(backgroundCtx)
+	go svc.janitor.Run(backgroundCtx)
 
 	s := &Server{
 		router:              chi.NewRouter(),
@@ -79,7 +76,9 @@ func NewServer(cfg *config.Config) (*Server, error) {
 		tournamentHandler:   tournamentHandler,
 		resultsHandler:      resultsHandler,
 		registrationHandler: registrationHandler,
-		db:                  database,
+		adminHandler:        adminHandler,
+		services:            svc,
+		stopBackground:      stopBackground,
 	}
 
 	s.setupMiddleware()
@@ -92,6 +91,7 @@ func (s *Server) setupMiddleware() {
 	s.router.Use(middleware.RequestID)
 	s.router.Use(middleware.RealIP)
 	s.router.Use(middleware.Logger)
+	s.router.Use(metricsMiddleware)
 	s.router.Use(middleware.Recoverer)
 	s.router.Use(middleware.Timeout(30 * time.Second))
 
@@ -114,12 +114,15 @@ func (s *Server) setupRoutes() {
 	// Health endpoints (no prefix)
 	s.router.Get("/health", s.handleHealth)
 	s.router.Get("/ready", s.handleReady)
+	s.router.Handle("/metrics", metrics.Handler())
 
 	// Override Swagger BasePath at runtime to match configured prefix
 	docs.SwaggerInfo.BasePath = s.cfg.Server.Prefix
 
 	// API routes - schack.se compatible (under configured prefix, e.g., /api)
 	s.router.Route(s.cfg.Server.Prefix, func(r chi.Router) {
+		r.Use(handlers.CacheHeaders)
+
 		// Swagger UI - accessible at {prefix}/swagger/*
 		r.Get("/swagger/*", httpSwagger.Handler(
 			httpSwagger.URL(s.cfg.Server.Prefix+"/swagger/doc.json"),
@@ -133,6 +136,7 @@ func (s *Server) setupRoutes() {
 		r.Get("/player/fornamn/{fornamn}/efternamn/{efternamn}", s.playerHandler.SearchPlayers)
 		r.Get("/player/batch", s.playerHandler.GetPlayers)           // mchess: Batch fetch ?ids=1,2,3&date=...
 		r.Get("/player/{id}/ratings", s.playerHandler.GetPlayerRatings) // mchess: Rating history
+		r.Post("/player/batch", s.playerHandler.PostPlayers)            // mchess: Batch fetch with per-player dates
 
 		// Organisation endpoints
 		r.Get("/organisation/federation", s.organisationHandler.GetFederation)
@@ -140,6 +144,7 @@ func (s *Server) setupRoutes() {
 		r.Get("/organisation/district/clubs/{districtid}", s.organisationHandler.GetClubsInDistrict)
 		r.Get("/organisation/club/{clubid}", s.organisationHandler.GetClub)
 		r.Get("/organisation/club/exists/{name}/{id}", s.organisationHandler.ClubNameExists)
+		r.Get("/organisation/club/batch", s.organisationHandler.GetClubs) // mchess: Batch fetch ?ids=1,2,3
 
 		// Rating list endpoints
 		r.Get("/ratinglist/federation/date/{ratingdate}/ratingtype/{ratingtype}/category/{category}", s.ratingListHandler.GetFederationRatingList)
@@ -157,6 +162,7 @@ func (s *Server) setupRoutes() {
 		r.Get("/tournament/group/updated/{startdate}/{enddate}", s.tournamentHandler.SearchUpdatedGroups)
 		r.Get("/tournament/group/updated/{startdate}/{enddate}/{districtid}", s.tournamentHandler.SearchUpdatedGroupsByDistrict)
 		r.Get("/tournament/class/id/{id}", s.tournamentHandler.GetTournamentFromClass)
+		r.Get("/tournament/tournament/batch", s.tournamentHandler.GetTournaments) // mchess: Batch fetch ?ids=1,2,3
 
 		// Tournament results endpoints
 		r.Get("/tournamentresults/table/id/{id}", s.resultsHandler.GetResultTable)
@@ -166,10 +172,25 @@ func (s *Server) setupRoutes() {
 		r.Get("/tournamentresults/team/roundresults/id/{id}", s.resultsHandler.GetTeamRoundResults)
 		r.Get("/tournamentresults/team/roundresults/id/{id}/memberid/{memberid}", s.resultsHandler.GetTeamRoundResultsForMember)
 		r.Get("/tournamentresults/game/memberid/{id}", s.resultsHandler.GetMemberGames)
+		r.Get("/tournamentresults/table/batch", s.resultsHandler.GetResultTables) // mchess: Batch fetch ?ids=1,2,3
 
 		// Team registration endpoint
 		r.Get("/tournamentteamregistration/tournament/{id}/club/{clubid}", s.registrationHandler.GetTeamRegistration)
 
+		// === mchess admin routes ===
+		// All admin routes require admin.token
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(handlers.AdminAuth(s.cfg.Admin.Token))
+			r.Get("/cache/stats", s.adminHandler.GetCacheStats)
+			r.Get("/upstream/drift", s.adminHandler.GetUpstreamDrift)
+			r.Get("/cache/player/{id}", s.adminHandler.ListPlayerCache)
+			r.Delete("/cache/player/{id}", s.adminHandler.DeletePlayerCache)
+			r.Delete("/cache/player/{id}/date/{date}", s.adminHandler.DeletePlayerCacheDate)
+			r.Post("/cache/player/{id}/refetch", s.adminHandler.RefetchPlayer)
+			r.Delete("/cache/{type}", s.adminHandler.PurgeCache)
+			r.Delete("/cache", s.adminHandler.PurgeCacheFetchedBefore)
+		})
+
 	})
 }
 
@@ -180,7 +201,7 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 
 func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
 	// Check database connection
-	if err := s.db.PingContext(r.Context()); err != nil {
+	if err := s.services.db.PingContext(r.Context()); err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusServiceUnavailable)
 		w.Write([]byte(`{"status":"not ready","reason":"database unavailable"}`))
@@ -193,7 +214,8 @@ func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
 
 // Close closes the server and its resources
 func (s *Server) Close() error {
-	return s.db.Close()
+	s.stopBackground()
+	return s.services.Close()
 }
 
 // StartServer starts the API server with graceful shutdown
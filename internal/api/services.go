@@ -0,0 +1,160 @@
+package api
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/msvens/mchess/internal/config"
+	"github.com/msvens/mchess/internal/db"
+	"github.com/msvens/mchess/internal/metrics"
+	"github.com/msvens/mchess/internal/repository"
+	"github.com/msvens/mchess/internal/service"
+	"github.com/msvens/mchess/internal/spec"
+	"github.com/msvens/mchess/internal/upstream"
+)
+
+// services holds the database, upstream client and services, shared by the
+// server and the cache commands
+type services struct {
+	db            *db.DB
+	upstream      *upstream.Client
+	stats         *service.StatsService
+	players       *service.PlayerService
+	tournaments   *service.TournamentService
+	results       *service.ResultsService
+	ratingLists   *service.RatingListService
+	organisations *service.OrganisationService
+	cacheAdmin    *service.CacheAdminService
+	warmer        *service.Warmer
+	janitor       *service.CacheJanitor
+}
+
+// newServices connects to the database and creates the upstream client and services
+func newServices(cfg *config.Config) (*services, error) {
+	// Load the API spec for response validation
+	var validator *spec.Validator
+	if cfg.Upstream.ValidateResponses {
+		apiSpec, err := spec.Load(cfg.Upstream.SpecFile)
+		if err != nil {
+			return nil, err
+		}
+		validator = spec.NewValidator(apiSpec)
+		specFile := cfg.Upstream.SpecFile
+		if specFile == "" {
+			specFile = "bundled"
+		}
+		slog.Info("Upstream response validation enabled", "spec", specFile)
+	}
+
+	// Initialize database
+	database, err := db.New(cfg.DBConnectionString())
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.Default.Register(metrics.NewDBStatsCollector(database.DB))
+
+	// Initialize upstream client
+	upstreamClient := upstream.NewClient(
+		cfg.Upstream.BaseURL,
+		cfg.Upstream.Timeout,
+		cfg.Upstream.RateLimit,
+		upstream.WithRetry(upstream.RetryPolicy{
+			MaxRetries: cfg.Upstream.MaxRetries,
+			BaseDelay:  cfg.Upstream.RetryBaseDelay,
+			MaxDelay:   cfg.Upstream.RetryMaxDelay,
+			Budget:     cfg.Upstream.RetryBudget,
+		}),
+		upstream.WithCircuitBreaker(upstream.BreakerPolicy{
+			FailureThreshold: cfg.Upstream.BreakerThreshold,
+			Cooldown:         cfg.Upstream.BreakerCooldown,
+		}),
+		upstream.WithCassettes(upstream.Mode(cfg.Upstream.Mode), cfg.Upstream.CassetteDir),
+		upstream.WithDriftDetection(cfg.Upstream.DriftDetection),
+		upstream.WithValidation(validator),
+	)
+	if cfg.Upstream.Mode != string(upstream.ModeLive) {
+		slog.Info("Upstream cassettes enabled", "mode", cfg.Upstream.Mode, "dir", cfg.Upstream.CassetteDir)
+	}
+
+	if cfg.Cache.MemoryEntries > 0 {
+		slog.Info("Player memory cache enabled", "entries", cfg.Cache.MemoryEntries, "maxMb", cfg.Cache.MemoryMaxMB)
+	}
+
+	// Initialize repositories
+	playerRepo := repository.NewPlayerRepository(database.DB,
+		repository.WithMemoryCache(cfg.Cache.MemoryEntries, int64(cfg.Cache.MemoryMaxMB)<<20))
+	tournamentRepo := repository.NewTournamentRepository(database.DB)
+	resultsRepo := repository.NewResultsRepository(database.DB)
+	ratingListRepo := repository.NewRatingListRepository(database.DB)
+	organisationRepo := repository.NewOrganisationRepository(database.DB)
+	statsRepo := repository.NewStatsRepository(database.DB)
+	cacheRepo := repository.NewCacheRepository(database.DB)
+
+	// Initialize services
+	statsService := service.NewStatsService(statsRepo, cfg)
+	playerService := service.NewPlayerService(playerRepo, upstreamClient, statsService, cfg)
+	tournamentService := service.NewTournamentService(tournamentRepo, upstreamClient, statsService, cfg)
+	resultsService := service.NewResultsService(resultsRepo, upstreamClient, tournamentService, statsService, cfg)
+	ratingListService := service.NewRatingListService(ratingListRepo, upstreamClient, statsService, cfg)
+	organisationService := service.NewOrganisationService(organisationRepo, upstreamClient, statsService, cfg)
+	cacheAdminService := service.NewCacheAdminService(cacheRepo, playerRepo)
+	janitor := service.NewCacheJanitor(cacheRepo, cfg)
+	warmer, err := service.NewWarmer(organisationService, ratingListService, playerService,
+		tournamentService, upstreamClient, cfg)
+	if err != nil {
+		database.Close()
+		return nil, err
+	}
+
+	return &services{
+		db:            database,
+		upstream:      upstreamClient,
+		stats:         statsService,
+		players:       playerService,
+		tournaments:   tournamentService,
+		results:       resultsService,
+		ratingLists:   ratingListService,
+		organisations: organisationService,
+		cacheAdmin:    cacheAdminService,
+		warmer:        warmer,
+		janitor:       janitor,
+	}, nil
+}
+
+// Close flushes buffered cache stats and closes the database
+func (s *services) Close() error {
+	// Write counts buffered since the last flush
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.stats.Flush(ctx); err != nil {
+		slog.Error("Failed to flush cache stats", "error", err)
+	}
+
+	return s.db.Close()
+}
+
+// WarmCache loads the targets configured under warm into the cache once,
+// as `mchess cache warm` does
+func WarmCache(ctx context.Context, cfg *config.Config) (service.WarmSummary, error) {
+	svc, err := newServices(cfg)
+	if err != nil {
+		return service.WarmSummary{}, err
+	}
+	defer svc.Close()
+
+	return svc.warmer.Warm(ctx), ctx.Err()
+}
+
+// PurgeExpiredCache deletes the cache entries that expired more than grace
+// ago once, as `mchess cache purge` does
+func PurgeExpiredCache(ctx context.Context, cfg *config.Config, grace time.Duration) (map[string]int64, error) {
+	svc, err := newServices(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer svc.Close()
+
+	return svc.janitor.Purge(ctx, grace)
+}
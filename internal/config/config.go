@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"slices"
 	"time"
 
 	"github.com/spf13/viper"
@@ -15,6 +16,9 @@ type Config struct {
 	DB       DBConfig
 	Upstream UpstreamConfig
 	Cache    CacheConfig
+	Batch    BatchConfig
+	Admin    AdminConfig
+	Warm     WarmConfig
 	Log      LogConfig
 }
 
@@ -36,10 +40,100 @@ type UpstreamConfig struct {
 	BaseURL   string
 	Timeout   time.Duration
 	RateLimit int // requests per second
+
+	MaxRetries     int           // Retries for failed requests (0 = no retries)
+	RetryBaseDelay time.Duration // Backoff before the first retry, doubled for each retry
+	RetryMaxDelay  time.Duration // Upper bound for backoff and Retry-After waits
+	RetryBudget    time.Duration // Overall time for a request, retries included (0 = unlimited)
+
+	BreakerThreshold int           // Consecutive failures before the circuit breaker opens (0 = disabled)
+	BreakerCooldown  time.Duration // How long the circuit breaker stays open before probing upstream again
+
+	Mode        string // live, record (save responses as cassettes) or replay (serve cassettes, no network)
+	CassetteDir string // Directory for recorded cassettes
+
+	DriftDetection bool // Log and count response fields that differ from our models
+
+	ValidateResponses bool   // Reject responses that don't match the API spec
+	SpecFile          string // OpenAPI spec used to validate responses ("" = the bundled spec)
 }
 
 type CacheConfig struct {
-	TTL time.Duration
+	TTL           time.Duration // TTL for current-month ratings (players and rating lists) near publication days
+	TournamentTTL time.Duration // TTL for tournaments that have not finished yet
+	ResultsTTL    time.Duration // TTL for results that are not finalized yet
+
+	OrganisationTTL     time.Duration // TTL for federation, district and club data
+	OrganisationRefresh time.Duration // How often expiring organisation data is refreshed (0 = disabled)
+
+	StatsFlushInterval time.Duration // How often buffered hit/miss counts are written to cache_stats
+
+	PurgeInterval  time.Duration // How often expired entries are deleted (0 = disabled)
+	PurgeGrace     time.Duration // How long expired entries are kept, to be served while upstream is down
+	PurgeBatchSize int           // Entries deleted per statement
+
+	MidMonthTTL        time.Duration // TTL for current-month ratings between publications (0 = TTL)
+	LaskPublicationDay int           // Day of month LASK ratings are published (0 = unknown)
+	FidePublicationDay int           // Day of month FIDE ratings are published (0 = unknown)
+	PublicationWindow  time.Duration // How long after a publication day TTL is used instead of MidMonthTTL
+
+	Overrides map[string]RatingTTLConfig // Rating TTL settings per resource (player, ratinglist)
+
+	MemoryEntries int // Players kept in an in-process LRU in front of player_cache (0 = disabled)
+	MemoryMaxMB   int // Approximate memory limit for the in-process LRU (0 = no limit)
+}
+
+// RatingTTLConfig holds the expiry settings for current-month ratings
+type RatingTTLConfig struct {
+	TTL                time.Duration
+	MidMonthTTL        time.Duration
+	LaskPublicationDay int
+	FidePublicationDay int
+	PublicationWindow  time.Duration
+}
+
+// RatingTTLResources are the resources whose rating TTL settings can be overridden
+var RatingTTLResources = []string{"player", "ratinglist"}
+
+// RatingTTL returns the expiry settings for current-month ratings of a
+// resource: its override if there is one, otherwise the cache settings
+func (c CacheConfig) RatingTTL(resource string) RatingTTLConfig {
+	if o, ok := c.Overrides[resource]; ok {
+		return o
+	}
+	return c.baseRatingTTL()
+}
+
+// baseRatingTTL returns the expiry settings for current-month ratings
+// without overrides
+func (c CacheConfig) baseRatingTTL() RatingTTLConfig {
+	return RatingTTLConfig{
+		TTL:                c.TTL,
+		MidMonthTTL:        c.MidMonthTTL,
+		LaskPublicationDay: c.LaskPublicationDay,
+		FidePublicationDay: c.FidePublicationDay,
+		PublicationWindow:  c.PublicationWindow,
+	}
+}
+
+type BatchConfig struct {
+	MaxSize        int           // Maximum IDs per batch request (0 = unlimited)
+	Workers        int           // Concurrent upstream fetches per batch request
+	ItemTimeout    time.Duration // Timeout for fetching a single item (0 = none)
+	DeadlineMargin time.Duration // Time reserved before the request deadline to respond with partial results
+}
+
+type AdminConfig struct {
+	Token string // Bearer token for the admin cache API (empty = disabled)
+}
+
+type WarmConfig struct {
+	Schedule          []string // When to warm the cache, e.g. "daily 05:00" (empty = never in serve)
+	Districts         []int    // Districts whose clubs' players are warmed
+	Clubs             []int    // Clubs whose players are warmed
+	RatingTypes       []int    // Club rating lists to warm (1 = standard, 6 = rapid, 7 = blitz)
+	ComingTournaments bool     // Warm coming tournaments
+	RateLimit         float64  // Upstream requests per second used for warming, within upstream.rateLimit
 }
 
 type LogConfig struct {
@@ -104,9 +198,51 @@ func setDefaults() {
 	viper.SetDefault("upstream.baseUrl", "https://member.schack.se/public/api/v1")
 	viper.SetDefault("upstream.timeout", "30s")
 	viper.SetDefault("upstream.rateLimit", 10)
+	viper.SetDefault("upstream.maxRetries", 2)
+	viper.SetDefault("upstream.retryBaseDelay", "200ms")
+	viper.SetDefault("upstream.retryMaxDelay", "5s")
+	viper.SetDefault("upstream.retryBudget", "60s")
+	viper.SetDefault("upstream.breakerThreshold", 5)
+	viper.SetDefault("upstream.breakerCooldown", "30s")
+	viper.SetDefault("upstream.mode", "live")
+	viper.SetDefault("upstream.cassetteDir", "cassettes")
+	viper.SetDefault("upstream.driftDetection", false)
+	viper.SetDefault("upstream.validateResponses", false)
+	viper.SetDefault("upstream.specFile", "")
 
 	// Cache defaults
 	viper.SetDefault("cache.ttl", "24h")
+	viper.SetDefault("cache.tournamentTtl", "1h")
+	viper.SetDefault("cache.resultsTtl", "5m")
+	viper.SetDefault("cache.organisationTtl", "168h")
+	viper.SetDefault("cache.organisationRefresh", "1h")
+	viper.SetDefault("cache.statsFlushInterval", "30s")
+	viper.SetDefault("cache.purgeInterval", "1h")
+	viper.SetDefault("cache.purgeGrace", "168h")
+	viper.SetDefault("cache.purgeBatchSize", 1000)
+	viper.SetDefault("cache.midMonthTtl", "0s")
+	viper.SetDefault("cache.laskPublicationDay", 1)
+	viper.SetDefault("cache.fidePublicationDay", 1)
+	viper.SetDefault("cache.publicationWindow", "72h")
+	viper.SetDefault("cache.memoryEntries", 0)
+	viper.SetDefault("cache.memoryMaxMb", 64)
+
+	// Batch defaults
+	viper.SetDefault("batch.maxSize", 100)
+	viper.SetDefault("batch.workers", 8)
+	viper.SetDefault("batch.itemTimeout", "10s")
+	viper.SetDefault("batch.deadlineMargin", "2s")
+
+	// Admin defaults
+	viper.SetDefault("admin.token", "")
+
+	// Warm defaults
+	viper.SetDefault("warm.schedule", []string{})
+	viper.SetDefault("warm.districts", []int{})
+	viper.SetDefault("warm.clubs", []int{})
+	viper.SetDefault("warm.ratingTypes", []int{1})
+	viper.SetDefault("warm.comingTournaments", false)
+	viper.SetDefault("warm.rateLimit", 2)
 
 	// Log defaults
 	viper.SetDefault("log.level", "info")
@@ -128,22 +264,76 @@ func parseConfig() error {
 		Name:     viper.GetString("db.name"),
 	}
 
-	timeout, err := time.ParseDuration(viper.GetString("upstream.timeout"))
-	if err != nil {
-		timeout = 30 * time.Second
-	}
 	cfg.Upstream = UpstreamConfig{
-		BaseURL:   viper.GetString("upstream.baseUrl"),
-		Timeout:   timeout,
-		RateLimit: viper.GetInt("upstream.rateLimit"),
-	}
+		BaseURL:        viper.GetString("upstream.baseUrl"),
+		Timeout:        parseDuration("upstream.timeout", 30*time.Second),
+		RateLimit:      viper.GetInt("upstream.rateLimit"),
+		MaxRetries:     viper.GetInt("upstream.maxRetries"),
+		RetryBaseDelay: parseDuration("upstream.retryBaseDelay", 200*time.Millisecond),
+		RetryMaxDelay:  parseDuration("upstream.retryMaxDelay", 5*time.Second),
+		RetryBudget:    parseDuration("upstream.retryBudget", 60*time.Second),
 
-	ttl, err := time.ParseDuration(viper.GetString("cache.ttl"))
-	if err != nil {
-		ttl = 24 * time.Hour
+		BreakerThreshold: viper.GetInt("upstream.breakerThreshold"),
+		BreakerCooldown:  parseDuration("upstream.breakerCooldown", 30*time.Second),
+
+		Mode:        viper.GetString("upstream.mode"),
+		CassetteDir: viper.GetString("upstream.cassetteDir"),
+
+		DriftDetection: viper.GetBool("upstream.driftDetection"),
+
+		ValidateResponses: viper.GetBool("upstream.validateResponses"),
+		SpecFile:          viper.GetString("upstream.specFile"),
+	}
+	switch cfg.Upstream.Mode {
+	case "live", "record", "replay":
+	default:
+		return fmt.Errorf("invalid upstream.mode %q: must be live, record or replay", cfg.Upstream.Mode)
 	}
+
 	cfg.Cache = CacheConfig{
-		TTL: ttl,
+		TTL:                 parseDuration("cache.ttl", 24*time.Hour),
+		TournamentTTL:       parseDuration("cache.tournamentTtl", time.Hour),
+		ResultsTTL:          parseDuration("cache.resultsTtl", 5*time.Minute),
+		OrganisationTTL:     parseDuration("cache.organisationTtl", 7*24*time.Hour),
+		OrganisationRefresh: parseDuration("cache.organisationRefresh", time.Hour),
+		StatsFlushInterval:  parseDuration("cache.statsFlushInterval", 30*time.Second),
+		PurgeInterval:       parseDuration("cache.purgeInterval", time.Hour),
+		PurgeGrace:          parseDuration("cache.purgeGrace", 7*24*time.Hour),
+		PurgeBatchSize:      viper.GetInt("cache.purgeBatchSize"),
+		MidMonthTTL:         parseDuration("cache.midMonthTtl", 0),
+		LaskPublicationDay:  viper.GetInt("cache.laskPublicationDay"),
+		FidePublicationDay:  viper.GetInt("cache.fidePublicationDay"),
+		PublicationWindow:   parseDuration("cache.publicationWindow", 72*time.Hour),
+		MemoryEntries:       viper.GetInt("cache.memoryEntries"),
+		MemoryMaxMB:         viper.GetInt("cache.memoryMaxMb"),
+	}
+	for _, key := range []string{"cache.laskPublicationDay", "cache.fidePublicationDay"} {
+		if day := viper.GetInt(key); day < 0 || day > 31 {
+			return fmt.Errorf("invalid %s %d: must be 1-31, or 0 for unknown", key, day)
+		}
+	}
+	if err := parseRatingTTLOverrides(); err != nil {
+		return err
+	}
+
+	cfg.Batch = BatchConfig{
+		MaxSize:        viper.GetInt("batch.maxSize"),
+		Workers:        viper.GetInt("batch.workers"),
+		ItemTimeout:    parseDuration("batch.itemTimeout", 10*time.Second),
+		DeadlineMargin: parseDuration("batch.deadlineMargin", 2*time.Second),
+	}
+
+	cfg.Admin = AdminConfig{
+		Token: viper.GetString("admin.token"),
+	}
+
+	cfg.Warm = WarmConfig{
+		Schedule:          viper.GetStringSlice("warm.schedule"),
+		Districts:         viper.GetIntSlice("warm.districts"),
+		Clubs:             viper.GetIntSlice("warm.clubs"),
+		RatingTypes:       viper.GetIntSlice("warm.ratingTypes"),
+		ComingTournaments: viper.GetBool("warm.comingTournaments"),
+		RateLimit:         viper.GetFloat64("warm.rateLimit"),
 	}
 
 	cfg.Log = LogConfig{
@@ -154,6 +344,48 @@ func parseConfig() error {
 	return nil
 }
 
+// parseRatingTTLOverrides parses cache.overrides. Settings an override
+// leaves out are taken from cache.
+func parseRatingTTLOverrides() error {
+	cfg.Cache.Overrides = make(map[string]RatingTTLConfig)
+	for resource := range viper.GetStringMap("cache.overrides") {
+		if !slices.Contains(RatingTTLResources, resource) {
+			return fmt.Errorf("invalid cache.overrides key %q: must be one of %v", resource, RatingTTLResources)
+		}
+
+		prefix := "cache.overrides." + resource + "."
+		o := cfg.Cache.baseRatingTTL()
+		o.TTL = parseDuration(prefix+"ttl", o.TTL)
+		o.MidMonthTTL = parseDuration(prefix+"midMonthTtl", o.MidMonthTTL)
+		o.PublicationWindow = parseDuration(prefix+"publicationWindow", o.PublicationWindow)
+		if viper.IsSet(prefix + "laskPublicationDay") {
+			o.LaskPublicationDay = viper.GetInt(prefix + "laskPublicationDay")
+		}
+		if viper.IsSet(prefix + "fidePublicationDay") {
+			o.FidePublicationDay = viper.GetInt(prefix + "fidePublicationDay")
+		}
+		for _, day := range []int{o.LaskPublicationDay, o.FidePublicationDay} {
+			if day < 0 || day > 31 {
+				return fmt.Errorf("invalid publication day %d in cache.overrides.%s: must be 1-31, or 0 for unknown", day, resource)
+			}
+		}
+		cfg.Cache.Overrides[resource] = o
+	}
+	return nil
+}
+
+// parseDuration returns the duration at key, or def if it is unset or invalid
+func parseDuration(key string, def time.Duration) time.Duration {
+	if !viper.IsSet(key) {
+		return def
+	}
+	d, err := time.ParseDuration(viper.GetString(key))
+	if err != nil {
+		return def
+	}
+	return d
+}
+
 // Get returns the current configuration
 func Get() *Config {
 	if cfg == nil {
@@ -0,0 +1,31 @@
+INSERT INTO schema_version (version, description)
+VALUES (2, 'Tournament structure cache');
+
+-- Tournament structure cache
+-- The same tournament can be looked up by tournament, group or class ID,
+-- so rows are keyed by the lookup used rather than the tournament ID
+CREATE TABLE tournament_cache (
+    lookup_type     TEXT NOT NULL,              -- 'tournament', 'group', 'class'
+    lookup_id       INTEGER NOT NULL,
+    tournament_id   INTEGER NOT NULL,
+    name            TEXT,
+    start_date      TIMESTAMPTZ,
+    end_date        TIMESTAMPTZ,
+    state           INTEGER,
+    -- Full response stored as JSONB for complete data
+    data            JSONB NOT NULL,
+    -- Cache metadata
+    fetched_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at      TIMESTAMPTZ,                -- NULL = never expires (finished tournament)
+
+    PRIMARY KEY (lookup_type, lookup_id)
+);
+
+CREATE INDEX idx_tournament_cache_expires
+    ON tournament_cache(expires_at)
+    WHERE expires_at IS NOT NULL;
+
+CREATE INDEX idx_tournament_cache_tournament
+    ON tournament_cache(tournament_id);
+
+INSERT INTO cache_stats (cache_type) VALUES ('tournament');
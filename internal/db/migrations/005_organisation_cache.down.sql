@@ -0,0 +1,3 @@
+DELETE FROM cache_stats WHERE cache_type = 'organisation';
+DROP TABLE IF EXISTS organisation_cache;
+DELETE FROM schema_version WHERE version = 5;
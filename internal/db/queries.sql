@@ -37,7 +37,11 @@ ON CONFLICT (member_id, rating_date) DO UPDATE SET
 
 -- name: DeleteExpiredCache :execrows
 DELETE FROM player_cache
-WHERE expires_at IS NOT NULL AND expires_at < NOW();
+WHERE ctid IN (
+    SELECT ctid FROM player_cache
+    WHERE expires_at < $1
+    LIMIT $2
+);
 
 -- name: GetCacheStats :one
 SELECT * FROM cache_stats WHERE cache_type = $1;
@@ -50,3 +54,132 @@ UPDATE cache_stats SET misses = misses + 1 WHERE cache_type = $1;
 
 -- name: IncrementUpstreamCalls :exec
 UPDATE cache_stats SET upstream_calls = upstream_calls + $2 WHERE cache_type = $1;
+
+-- name: GetTournamentCache :one
+SELECT * FROM tournament_cache
+WHERE lookup_type = $1 AND lookup_id = $2
+AND (expires_at IS NULL OR expires_at > NOW());
+
+-- name: UpsertTournamentCache :exec
+INSERT INTO tournament_cache (
+    lookup_type, lookup_id, tournament_id, name, start_date, end_date, state,
+    data, fetched_at, expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+)
+ON CONFLICT (lookup_type, lookup_id) DO UPDATE SET
+    tournament_id = EXCLUDED.tournament_id,
+    name = EXCLUDED.name,
+    start_date = EXCLUDED.start_date,
+    end_date = EXCLUDED.end_date,
+    state = EXCLUDED.state,
+    data = EXCLUDED.data,
+    fetched_at = EXCLUDED.fetched_at,
+    expires_at = EXCLUDED.expires_at;
+
+-- name: GetResultsCache :one
+SELECT * FROM results_cache
+WHERE group_id = $1 AND result_type = $2
+AND (expires_at IS NULL OR expires_at > NOW());
+
+-- name: UpsertResultsCache :exec
+INSERT INTO results_cache (
+    group_id, result_type, data, fetched_at, expires_at
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+ON CONFLICT (group_id, result_type) DO UPDATE SET
+    data = EXCLUDED.data,
+    fetched_at = EXCLUDED.fetched_at,
+    expires_at = EXCLUDED.expires_at;
+
+-- name: GetRatingListSnapshot :one
+SELECT * FROM rating_list_snapshot
+WHERE scope = $1 AND scope_id = $2 AND rating_date = $3
+AND rating_type = $4 AND category = $5
+AND (expires_at IS NULL OR expires_at > NOW());
+
+-- name: UpsertRatingListSnapshot :exec
+INSERT INTO rating_list_snapshot (
+    scope, scope_id, rating_date, rating_type, category, data, fetched_at, expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+)
+ON CONFLICT (scope, scope_id, rating_date, rating_type, category) DO UPDATE SET
+    data = EXCLUDED.data,
+    fetched_at = EXCLUDED.fetched_at,
+    expires_at = EXCLUDED.expires_at;
+
+-- name: GetOrganisationCache :one
+SELECT * FROM organisation_cache
+WHERE kind = $1 AND id = $2 AND expires_at > NOW();
+
+-- name: UpsertOrganisationCache :exec
+INSERT INTO organisation_cache (
+    kind, id, data, fetched_at, expires_at
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+ON CONFLICT (kind, id) DO UPDATE SET
+    data = EXCLUDED.data,
+    fetched_at = EXCLUDED.fetched_at,
+    expires_at = EXCLUDED.expires_at;
+
+-- name: ListExpiringOrganisationCache :many
+SELECT kind, id FROM organisation_cache
+WHERE expires_at < $1
+ORDER BY expires_at;
+
+-- name: AddCacheStats :exec
+INSERT INTO cache_stats (cache_type, hits, misses, upstream_calls)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (cache_type) DO UPDATE SET
+    hits = COALESCE(cache_stats.hits, 0) + EXCLUDED.hits,
+    misses = COALESCE(cache_stats.misses, 0) + EXCLUDED.misses,
+    upstream_calls = COALESCE(cache_stats.upstream_calls, 0) + EXCLUDED.upstream_calls;
+
+-- name: ListCacheStats :many
+SELECT * FROM cache_stats ORDER BY cache_type;
+
+-- name: GetStalePlayerCache :one
+SELECT data, fetched_at FROM player_cache
+WHERE member_id = $1 AND rating_date = $2;
+
+-- name: GetStalePlayerCacheByFideID :one
+SELECT data, fetched_at FROM player_cache
+WHERE fide_id = $1 AND rating_date = $2
+ORDER BY fetched_at DESC
+LIMIT 1;
+
+-- name: GetStaleTournamentCache :one
+SELECT data, fetched_at FROM tournament_cache
+WHERE lookup_type = $1 AND lookup_id = $2;
+
+-- name: GetStaleResultsCache :one
+SELECT data, fetched_at FROM results_cache
+WHERE group_id = $1 AND result_type = $2;
+
+-- name: GetStaleRatingListSnapshot :one
+SELECT data, fetched_at FROM rating_list_snapshot
+WHERE scope = $1 AND scope_id = $2 AND rating_date = $3
+AND rating_type = $4 AND category = $5;
+
+-- name: GetStaleOrganisationCache :one
+SELECT data, fetched_at FROM organisation_cache
+WHERE kind = $1 AND id = $2;
+
+-- name: ListPlayerCacheByMember :many
+SELECT rating_date, fetched_at, expires_at,
+    expires_at IS NOT NULL AND expires_at <= NOW() AS expired, data
+FROM player_cache
+WHERE member_id = $1
+ORDER BY rating_date DESC;
+
+-- name: DeletePlayerCache :execrows
+DELETE FROM player_cache WHERE member_id = $1;
+
+-- name: DeletePlayerCacheDate :execrows
+DELETE FROM player_cache WHERE member_id = $1 AND rating_date = $2;
+
+-- name: PurgePlayerCacheFetchedBefore :execrows
+DELETE FROM player_cache WHERE fetched_at < $1;
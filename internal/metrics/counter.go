@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"io"
+	"sort"
+	"sync"
+)
+
+// CounterVec is a counter partitioned by label values
+type CounterVec struct {
+	name   string
+	help   string
+	labels []string
+
+	mu     sync.Mutex
+	series map[string]*counterSeries
+}
+
+type counterSeries struct {
+	values []string
+	value  float64
+}
+
+// NewCounterVec creates a counter and registers it with the default registry
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{
+		name:   name,
+		help:   help,
+		labels: labels,
+		series: make(map[string]*counterSeries),
+	}
+	Default.Register(c)
+	return c
+}
+
+// Inc increments the counter for the given label values by 1
+func (c *CounterVec) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+// Add increments the counter for the given label values by v
+func (c *CounterVec) Add(v float64, values ...string) {
+	checkLabels(c.name, c.labels, values)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := seriesKey(values)
+	s, ok := c.series[key]
+	if !ok {
+		s = &counterSeries{values: append([]string(nil), values...)}
+		c.series[key] = s
+	}
+	s.value += v
+}
+
+// Name implements Collector
+func (c *CounterVec) Name() string {
+	return c.name
+}
+
+// Write implements Collector
+func (c *CounterVec) Write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	writeHeader(w, c.name, c.help, "counter")
+	for _, key := range sortedKeys(c.series) {
+		s := c.series[key]
+		writeSample(w, c.name, c.labels, s.values, s.value)
+	}
+}
+
+// sortedKeys returns the keys of a series map in sorted order, so output is stable
+func sortedKeys[T any](series map[string]T) []string {
+	keys := make([]string, 0, len(series))
+	for key := range series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
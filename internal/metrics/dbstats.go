@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"database/sql"
+	"io"
+)
+
+// DBStatsCollector exposes the connection pool statistics of a *sql.DB
+type DBStatsCollector struct {
+	db *sql.DB
+}
+
+// NewDBStatsCollector creates a collector for the pool statistics of db.
+// It is not registered automatically.
+func NewDBStatsCollector(db *sql.DB) *DBStatsCollector {
+	return &DBStatsCollector{db: db}
+}
+
+// Name implements Collector
+func (c *DBStatsCollector) Name() string {
+	return "mchess_db"
+}
+
+// Write implements Collector
+func (c *DBStatsCollector) Write(w io.Writer) {
+	stats := c.db.Stats()
+
+	gauge := func(name, help string, value float64) {
+		writeHeader(w, name, help, "gauge")
+		writeSample(w, name, nil, nil, value)
+	}
+	counter := func(name, help string, value float64) {
+		writeHeader(w, name, help, "counter")
+		writeSample(w, name, nil, nil, value)
+	}
+
+	gauge("mchess_db_max_open_connections", "Maximum number of open connections to the database.", float64(stats.MaxOpenConnections))
+	gauge("mchess_db_open_connections", "Number of established connections, both in use and idle.", float64(stats.OpenConnections))
+	gauge("mchess_db_in_use_connections", "Number of connections currently in use.", float64(stats.InUse))
+	gauge("mchess_db_idle_connections", "Number of idle connections.", float64(stats.Idle))
+	counter("mchess_db_wait_count_total", "Total number of connections waited for.", float64(stats.WaitCount))
+	counter("mchess_db_wait_duration_seconds_total", "Total time blocked waiting for a new connection.", stats.WaitDuration.Seconds())
+	counter("mchess_db_max_idle_closed_total", "Total number of connections closed due to SetMaxIdleConns.", float64(stats.MaxIdleClosed))
+	counter("mchess_db_max_idle_time_closed_total", "Total number of connections closed due to SetConnMaxIdleTime.", float64(stats.MaxIdleTimeClosed))
+	counter("mchess_db_max_lifetime_closed_total", "Total number of connections closed due to SetConnMaxLifetime.", float64(stats.MaxLifetimeClosed))
+}
@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"io"
+	"math"
+	"sync"
+)
+
+// DefBuckets are the default histogram buckets, in seconds, suited to
+// request latencies
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// HistogramVec is a histogram partitioned by label values
+type HistogramVec struct {
+	name    string
+	help    string
+	labels  []string
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	values []string
+	counts []uint64 // per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+// NewHistogramVec creates a histogram and registers it with the default registry.
+// buckets must be sorted in increasing order; nil means DefBuckets.
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if buckets == nil {
+		buckets = DefBuckets
+	}
+	h := &HistogramVec{
+		name:    name,
+		help:    help,
+		labels:  labels,
+		buckets: buckets,
+		series:  make(map[string]*histogramSeries),
+	}
+	Default.Register(h)
+	return h
+}
+
+// Observe adds an observation for the given label values
+func (h *HistogramVec) Observe(v float64, values ...string) {
+	checkLabels(h.name, h.labels, values)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	key := seriesKey(values)
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogramSeries{
+			values: append([]string(nil), values...),
+			counts: make([]uint64, len(h.buckets)),
+		}
+		h.series[key] = s
+	}
+
+	for i, upper := range h.buckets {
+		if v <= upper {
+			s.counts[i]++
+			break
+		}
+	}
+	s.count++
+	s.sum += v
+}
+
+// Name implements Collector
+func (h *HistogramVec) Name() string {
+	return h.name
+}
+
+// Write implements Collector
+func (h *HistogramVec) Write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	writeHeader(w, h.name, h.help, "histogram")
+
+	bucketLabels := append(append([]string(nil), h.labels...), "le")
+	for _, key := range sortedKeys(h.series) {
+		s := h.series[key]
+
+		var cumulative uint64
+		for i, upper := range h.buckets {
+			cumulative += s.counts[i]
+			writeSample(w, h.name+"_bucket", bucketLabels, append(append([]string(nil), s.values...), formatValue(upper)), float64(cumulative))
+		}
+		writeSample(w, h.name+"_bucket", bucketLabels, append(append([]string(nil), s.values...), formatValue(math.Inf(1))), float64(s.count))
+		writeSample(w, h.name+"_sum", h.labels, s.values, s.sum)
+		writeSample(w, h.name+"_count", h.labels, s.values, float64(s.count))
+	}
+}
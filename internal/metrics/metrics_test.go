@@ -0,0 +1,82 @@
+package metrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/msvens/mchess/internal/metrics"
+)
+
+func TestRegistry_Write(t *testing.T) {
+	requests := metrics.NewCounterVec("test_requests_total", "Total requests.\nBy path\\method.", "path", "method")
+	requests.Inc("/b", "GET")
+	requests.Add(2.5, `/a"quoted"\slash`+"\n", "POST")
+
+	latency := metrics.NewHistogramVec("test_latency_seconds", "Request latency.", []float64{0.1, 0.5, 1}, "route")
+	latency.Observe(0.05, "/x")
+	latency.Observe(0.3, "/x")
+	latency.Observe(0.3, "/x")
+	latency.Observe(2, "/x")
+	latency.Observe(1, "/y")
+
+	plain := metrics.NewCounterVec("test_plain_total", "No labels.")
+	plain.Inc()
+
+	registry := metrics.NewRegistry()
+	registry.Register(requests)
+	registry.Register(latency)
+	registry.Register(plain)
+
+	want := `# HELP test_latency_seconds Request latency.
+# TYPE test_latency_seconds histogram
+test_latency_seconds_bucket{route="/x",le="0.1"} 1
+test_latency_seconds_bucket{route="/x",le="0.5"} 3
+test_latency_seconds_bucket{route="/x",le="1"} 3
+test_latency_seconds_bucket{route="/x",le="+Inf"} 4
+test_latency_seconds_sum{route="/x"} 2.65
+test_latency_seconds_count{route="/x"} 4
+test_latency_seconds_bucket{route="/y",le="0.1"} 0
+test_latency_seconds_bucket{route="/y",le="0.5"} 0
+test_latency_seconds_bucket{route="/y",le="1"} 1
+test_latency_seconds_bucket{route="/y",le="+Inf"} 1
+test_latency_seconds_sum{route="/y"} 1
+test_latency_seconds_count{route="/y"} 1
+# HELP test_plain_total No labels.
+# TYPE test_plain_total counter
+test_plain_total 1
+# HELP test_requests_total Total requests.\nBy path\\method.
+# TYPE test_requests_total counter
+test_requests_total{path="/a\"quoted\"\\slash\n",method="POST"} 2.5
+test_requests_total{path="/b",method="GET"} 1
+`
+
+	var b strings.Builder
+	registry.Write(&b)
+	if got := b.String(); got != want {
+		t.Errorf("Write output mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+
+	t.Run("Handler", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		registry.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+		if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+			t.Errorf("Content-Type: got %s, want text/plain; version=0.0.4", ct)
+		}
+		if rr.Body.String() != want {
+			t.Errorf("Handler output differs from Write")
+		}
+	})
+}
+
+func TestCounterVec_WrongLabelCount_Panics(t *testing.T) {
+	c := metrics.NewCounterVec("test_panic_total", "Panics.", "a")
+	defer func() {
+		if recover() == nil {
+			t.Error("Inc with 2 values for 1 label: got no panic")
+		}
+	}()
+	c.Inc("x", "y")
+}
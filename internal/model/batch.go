@@ -0,0 +1,41 @@
+package model
+
+// BatchError represents an error for a specific ID in a batch request
+// @Description Error information for a failed lookup in a batch request
+// @name BatchError
+type BatchError struct {
+	ID    int    `json:"id" example:"99999"`
+	Error string `json:"error" example:"upstream fetch: not found"`
+}
+
+// ClubsResponse is the response for batch club requests
+// @Description Response containing multiple clubs and any errors
+// @name ClubsResponse
+type ClubsResponse struct {
+	Clubs  []Club       `json:"clubs"`
+	Errors []BatchError `json:"errors,omitempty"`
+}
+
+// TournamentsResponse is the response for batch tournament requests
+// @Description Response containing multiple tournaments and any errors
+// @name TournamentsResponse
+type TournamentsResponse struct {
+	Tournaments []Tournament `json:"tournaments"`
+	Errors      []BatchError `json:"errors,omitempty"`
+}
+
+// ResultTable holds the individual standings for one group
+// @Description Individual standings for a tournament group
+// @name ResultTable
+type ResultTable struct {
+	GroupID int                   `json:"groupId" example:"12345"`
+	Results []TournamentEndResult `json:"results"`
+}
+
+// ResultTablesResponse is the response for batch result table requests
+// @Description Response containing multiple result tables and any errors
+// @name ResultTablesResponse
+type ResultTablesResponse struct {
+	Tables []ResultTable `json:"tables"`
+	Errors []BatchError  `json:"errors,omitempty"`
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// CacheStats holds hit/miss accounting for one cache type
+// @Description Cache statistics for a single cache type
+// @name CacheStats
+type CacheStats struct {
+	CacheType     string    `json:"cacheType" example:"player"`
+	Hits          int64     `json:"hits" example:"950"`
+	Misses        int64     `json:"misses" example:"50"`
+	UpstreamCalls int64     `json:"upstreamCalls" example:"52"`
+	HitRatio      float64   `json:"hitRatio" example:"0.95"`
+	LastReset     time.Time `json:"lastReset"`
+}
+
+// CacheStatsResponse is the response for the cache statistics endpoint
+// @Description Cache statistics for all cache types
+// @name CacheStatsResponse
+type CacheStatsResponse struct {
+	Stats []CacheStats `json:"stats"`
+}
+
+// CachedPlayer is a player_cache entry as listed by the admin API
+// @Description A cached player for one rating date, including expired entries
+// @name CachedPlayer
+type CachedPlayer struct {
+	RatingDate string          `json:"ratingDate" example:"2024-06-01"`
+	FetchedAt  time.Time       `json:"fetchedAt"`
+	ExpiresAt  *time.Time      `json:"expiresAt,omitempty"` // Never expires if not set
+	Expired    bool            `json:"expired" example:"false"`
+	Data       json.RawMessage `json:"data" swaggertype:"object"` // Stored upstream response
+}
+
+// PlayerCacheResponse is the response for listing a member's cache entries
+// @Description Cache entries for a member, newest rating date first
+// @name PlayerCacheResponse
+type PlayerCacheResponse struct {
+	MemberID int            `json:"memberId" example:"12345"`
+	Entries  []CachedPlayer `json:"entries"`
+}
+
+// CachePurgeResponse is the response for cache deletes and purges
+// @Description Number of cache entries deleted
+// @name CachePurgeResponse
+type CachePurgeResponse struct {
+	Deleted int64 `json:"deleted" example:"12"`
+}
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// UpstreamDrift holds the last observed differences between upstream
+// responses and our models for one upstream endpoint
+// @Description Fields upstream sent that our models lack, or that our models have and upstream no longer sends
+// @name UpstreamDrift
+type UpstreamDrift struct {
+	Endpoint      string    `json:"endpoint" example:"/player/{id}/date/{date}"`
+	Path          string    `json:"path" example:"/player/12345/date/2024-06-01"` // Last request with differences
+	UnknownFields []string  `json:"unknownFields,omitempty" example:"elo.fideTitle"`
+	MissingFields []string  `json:"missingFields,omitempty" example:"clubId"`
+	Count         int64     `json:"count" example:"3"` // Responses with differences
+	FirstSeen     time.Time `json:"firstSeen"`
+	LastSeen      time.Time `json:"lastSeen"`
+}
+
+// UpstreamDriftResponse is the response for the upstream drift endpoint
+// @Description Observed upstream schema drift per endpoint
+// @name UpstreamDriftResponse
+type UpstreamDriftResponse struct {
+	Enabled   bool            `json:"enabled" example:"true"`
+	Endpoints []UpstreamDrift `json:"endpoints"`
+}
@@ -3,7 +3,8 @@ package model
 import "time"
 
 // PlayerInfo represents a player from the schack.se API
-// This matches the upstream PlayerInfoDto exactly
+// This matches the upstream PlayerInfoDto, plus the _cached and _cachedAt
+// fields set when the player is served from the cache
 // @Description Player information from the Swedish Chess Federation
 // @name PlayerInfo
 type PlayerInfo struct {
@@ -18,6 +19,9 @@ type PlayerInfo struct {
 	ClubID    int         `json:"clubId,omitempty" example:"101"`
 	Elo       *EloRating  `json:"elo,omitempty"`
 	Lask      *LaskRating `json:"lask,omitempty"`
+
+	Cached   bool       `json:"_cached,omitempty" example:"true"`
+	CachedAt *time.Time `json:"_cachedAt,omitempty"`
 }
 
 // EloRating represents FIDE ELO ratings
@@ -72,8 +76,19 @@ type PlayersResponse struct {
 // @Description Error information for a failed player lookup
 // @name PlayerError
 type PlayerError struct {
-	ID    int    `json:"id" example:"99999"`
-	Error string `json:"error" example:"player not found"`
+	ID     int    `json:"id,omitempty" example:"99999"` // Unset for FIDE ID lookups
+	FideID int    `json:"fideId,omitempty" example:"1503014"`
+	Date   string `json:"date,omitempty" example:"2024-06-01"` // Set for POST batch requests
+	Error  string `json:"error" example:"player not found"`
+}
+
+// PlayerBatchItem is one entry in a POST batch player request
+// @Description Player to look up by member ID or FIDE ID, with the date to look up the rating for
+// @name PlayerBatchItem
+type PlayerBatchItem struct {
+	ID     int    `json:"id,omitempty" example:"12345"`
+	FideID int    `json:"fideId,omitempty" example:"1503014"`
+	Date   string `json:"date,omitempty" example:"2024-06-01"` // YYYY-MM-DD, defaults to current date
 }
 
 // RatingHistoryResponse is the response for player rating history
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// cacheTables maps cache types (as in cache_stats) to the tables holding their entries
+var cacheTables = map[string]string{
+	"player":       "player_cache",
+	"tournament":   "tournament_cache",
+	"results":      "results_cache",
+	"ratinglist":   "rating_list_snapshot",
+	"organisation": "organisation_cache",
+}
+
+// CacheTypes returns the cache types, sorted
+func CacheTypes() []string {
+	types := make([]string, 0, len(cacheTables))
+	for cacheType := range cacheTables {
+		types = append(types, cacheType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// CacheRepository handles operations on all cache tables
+type CacheRepository struct {
+	db *sql.DB
+}
+
+// NewCacheRepository creates a new cache repository
+func NewCacheRepository(db *sql.DB) *CacheRepository {
+	return &CacheRepository{db: db}
+}
+
+// Purge removes the entries of a cache type that were fetched before
+// fetchedBefore, or all of them if fetchedBefore is zero
+func (r *CacheRepository) Purge(ctx context.Context, cacheType string, fetchedBefore time.Time) (int64, error) {
+	table, ok := cacheTables[cacheType]
+	if !ok {
+		return 0, fmt.Errorf("unknown cache type %q", cacheType)
+	}
+
+	query := `DELETE FROM ` + table
+	var args []any
+	if !fetchedBefore.IsZero() {
+		query += ` WHERE fetched_at < $1`
+		args = append(args, fetchedBefore)
+	}
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("purge %s: %w", table, err)
+	}
+	return result.RowsAffected()
+}
+
+// DeleteExpired removes up to limit entries of a cache type that expired
+// before expiredBefore. Entries without an expiry are never removed.
+func (r *CacheRepository) DeleteExpired(ctx context.Context, cacheType string, expiredBefore time.Time, limit int) (int64, error) {
+	table, ok := cacheTables[cacheType]
+	if !ok {
+		return 0, fmt.Errorf("unknown cache type %q", cacheType)
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM `+table+`
+		WHERE ctid IN (
+			SELECT ctid FROM `+table+`
+			WHERE expires_at < $1
+			LIMIT $2
+		)`, expiredBefore, limit)
+	if err != nil {
+		return 0, fmt.Errorf("delete expired %s: %w", table, err)
+	}
+	return result.RowsAffected()
+}
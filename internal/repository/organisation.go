@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Organisation cache kinds, one per upstream organisation endpoint
+const (
+	OrganisationKindFederation    = "federation"
+	OrganisationKindDistricts     = "districts"
+	OrganisationKindDistrictClubs = "districtclubs"
+	OrganisationKindClub          = "club"
+)
+
+// OrganisationKey identifies an organisation cache entry
+type OrganisationKey struct {
+	Kind string
+	ID   int // District or club ID, 0 when not applicable
+}
+
+// OrganisationRepository handles organisation cache database operations
+type OrganisationRepository struct {
+	db *sql.DB
+}
+
+// NewOrganisationRepository creates a new organisation repository
+func NewOrganisationRepository(db *sql.DB) *OrganisationRepository {
+	return &OrganisationRepository{db: db}
+}
+
+// Get retrieves a cached organisation entry and decodes it into result.
+// Returns false if there is no valid cache entry, and otherwise the time it was fetched.
+func (r *OrganisationRepository) Get(ctx context.Context, key OrganisationKey, result interface{}) (bool, time.Time, error) {
+	query := `
+		SELECT data, fetched_at FROM organisation_cache
+		WHERE kind = $1 AND id = $2 AND expires_at > NOW()`
+
+	var data []byte
+	var fetchedAt time.Time
+	err := r.db.QueryRowContext(ctx, query, key.Kind, key.ID).Scan(&data, &fetchedAt)
+	if err == sql.ErrNoRows {
+		return false, time.Time{}, nil
+	}
+	if err != nil {
+		return false, time.Time{}, fmt.Errorf("query organisation cache: %w", err)
+	}
+
+	if err := json.Unmarshal(data, result); err != nil {
+		return false, time.Time{}, fmt.Errorf("unmarshal organisation data: %w", err)
+	}
+
+	return true, fetchedAt, nil
+}
+
+// GetStale retrieves a cached organisation entry including expired entries.
+// Returns false if there is no cache entry, and otherwise the time it was fetched.
+func (r *OrganisationRepository) GetStale(ctx context.Context, key OrganisationKey, result interface{}) (bool, time.Time, error) {
+	query := `
+		SELECT data, fetched_at FROM organisation_cache
+		WHERE kind = $1 AND id = $2`
+
+	var data []byte
+	var fetchedAt time.Time
+	err := r.db.QueryRowContext(ctx, query, key.Kind, key.ID).Scan(&data, &fetchedAt)
+	if err == sql.ErrNoRows {
+		return false, time.Time{}, nil
+	}
+	if err != nil {
+		return false, time.Time{}, fmt.Errorf("query stale organisation cache: %w", err)
+	}
+
+	if err := json.Unmarshal(data, result); err != nil {
+		return false, time.Time{}, fmt.Errorf("unmarshal organisation data: %w", err)
+	}
+
+	return true, fetchedAt, nil
+}
+
+// Save stores an organisation entry in the cache
+func (r *OrganisationRepository) Save(ctx context.Context, key OrganisationKey, value interface{}, expiresAt time.Time) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("marshal organisation data: %w", err)
+	}
+
+	query := `
+		INSERT INTO organisation_cache (
+			kind, id, data, fetched_at, expires_at
+		) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (kind, id) DO UPDATE SET
+			data = EXCLUDED.data,
+			fetched_at = EXCLUDED.fetched_at,
+			expires_at = EXCLUDED.expires_at`
+
+	_, err = r.db.ExecContext(ctx, query, key.Kind, key.ID, data, time.Now(), expiresAt)
+	if err != nil {
+		return fmt.Errorf("insert organisation cache: %w", err)
+	}
+
+	return nil
+}
+
+// ListExpiring returns the keys of all entries that expire between after and before
+func (r *OrganisationRepository) ListExpiring(ctx context.Context, after, before time.Time) ([]OrganisationKey, error) {
+	query := `
+		SELECT kind, id FROM organisation_cache
+		WHERE expires_at BETWEEN $1 AND $2
+		ORDER BY expires_at`
+
+	rows, err := r.db.QueryContext(ctx, query, after, before)
+	if err != nil {
+		return nil, fmt.Errorf("query expiring organisation cache: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []OrganisationKey
+	for rows.Next() {
+		var key OrganisationKey
+		if err := rows.Scan(&key.Kind, &key.ID); err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// Delete removes an organisation entry from the cache
+func (r *OrganisationRepository) Delete(ctx context.Context, key OrganisationKey) (int64, error) {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM organisation_cache WHERE kind = $1 AND id = $2`, key.Kind, key.ID)
+	if err != nil {
+		return 0, fmt.Errorf("delete organisation cache: %w", err)
+	}
+	return result.RowsAffected()
+}
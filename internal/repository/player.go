@@ -12,23 +12,50 @@ import (
 
 // PlayerRepository handles player cache database operations
 type PlayerRepository struct {
-	db *sql.DB
+	db     *sql.DB
+	memory *playerMemory // In-memory tier for lookups by member ID (nil = disabled)
+}
+
+// PlayerOption configures optional PlayerRepository behaviour
+type PlayerOption func(*PlayerRepository)
+
+// WithMemoryCache keeps up to maxEntries players, using about maxBytes at
+// most (0 = no memory limit), in an in-process LRU cache in front of
+// player_cache. Lookups by member ID and rating date are served from it.
+// maxEntries 0 leaves it disabled.
+func WithMemoryCache(maxEntries int, maxBytes int64) PlayerOption {
+	return func(r *PlayerRepository) {
+		if maxEntries > 0 {
+			r.memory = newPlayerMemory(maxEntries, maxBytes)
+		}
+	}
 }
 
 // NewPlayerRepository creates a new player repository
-func NewPlayerRepository(db *sql.DB) *PlayerRepository {
-	return &PlayerRepository{db: db}
+func NewPlayerRepository(db *sql.DB, opts ...PlayerOption) *PlayerRepository {
+	r := &PlayerRepository{db: db}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 // Get retrieves a cached player by member ID and rating date
 func (r *PlayerRepository) Get(ctx context.Context, memberID int, ratingDate time.Time) (*model.PlayerInfo, error) {
+	if player, ok := r.memory.get(memberID, ratingDate); ok {
+		return player, nil
+	}
+	gen := r.memory.generation() // Before the read, see playerMemory
+
 	query := `
-		SELECT data FROM player_cache
+		SELECT data, fetched_at, expires_at FROM player_cache
 		WHERE member_id = $1 AND rating_date = $2
 		AND (expires_at IS NULL OR expires_at > NOW())`
 
 	var data []byte
-	err := r.db.QueryRowContext(ctx, query, memberID, ratingDate).Scan(&data)
+	var fetchedAt time.Time
+	var expiresAt *time.Time
+	err := r.db.QueryRowContext(ctx, query, memberID, ratingDate).Scan(&data, &fetchedAt, &expiresAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -40,6 +67,8 @@ func (r *PlayerRepository) Get(ctx context.Context, memberID int, ratingDate tim
 	if err := json.Unmarshal(data, &player); err != nil {
 		return nil, fmt.Errorf("unmarshal player data: %w", err)
 	}
+	r.memory.put(player, ratingDate, fetchedAt, expiresAt, len(data), gen)
+	setCached(&player, fetchedAt)
 
 	return &player, nil
 }
@@ -47,12 +76,13 @@ func (r *PlayerRepository) Get(ctx context.Context, memberID int, ratingDate tim
 // GetByFideID retrieves a cached player by FIDE ID and rating date
 func (r *PlayerRepository) GetByFideID(ctx context.Context, fideID int, ratingDate time.Time) (*model.PlayerInfo, error) {
 	query := `
-		SELECT data FROM player_cache
+		SELECT data, fetched_at FROM player_cache
 		WHERE fide_id = $1 AND rating_date = $2
 		AND (expires_at IS NULL OR expires_at > NOW())`
 
 	var data []byte
-	err := r.db.QueryRowContext(ctx, query, fideID, ratingDate).Scan(&data)
+	var fetchedAt time.Time
+	err := r.db.QueryRowContext(ctx, query, fideID, ratingDate).Scan(&data, &fetchedAt)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -64,32 +94,102 @@ func (r *PlayerRepository) GetByFideID(ctx context.Context, fideID int, ratingDa
 	if err := json.Unmarshal(data, &player); err != nil {
 		return nil, fmt.Errorf("unmarshal player data: %w", err)
 	}
+	setCached(&player, fetchedAt)
 
 	return &player, nil
 }
 
+// GetStale retrieves a cached player by member ID and rating date, including
+// expired entries. Returns the time the entry was fetched, or nil if not cached.
+func (r *PlayerRepository) GetStale(ctx context.Context, memberID int, ratingDate time.Time) (*model.PlayerInfo, time.Time, error) {
+	query := `
+		SELECT data, fetched_at FROM player_cache
+		WHERE member_id = $1 AND rating_date = $2`
+
+	var data []byte
+	var fetchedAt time.Time
+	err := r.db.QueryRowContext(ctx, query, memberID, ratingDate).Scan(&data, &fetchedAt)
+	if err == sql.ErrNoRows {
+		return nil, time.Time{}, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("query stale player cache: %w", err)
+	}
+
+	var player model.PlayerInfo
+	if err := json.Unmarshal(data, &player); err != nil {
+		return nil, time.Time{}, fmt.Errorf("unmarshal player data: %w", err)
+	}
+	setCached(&player, fetchedAt)
+
+	return &player, fetchedAt, nil
+}
+
+// GetStaleByFideID retrieves a cached player by FIDE ID and rating date,
+// including expired entries. Returns the time the entry was fetched, or nil if not cached.
+func (r *PlayerRepository) GetStaleByFideID(ctx context.Context, fideID int, ratingDate time.Time) (*model.PlayerInfo, time.Time, error) {
+	query := `
+		SELECT data, fetched_at FROM player_cache
+		WHERE fide_id = $1 AND rating_date = $2
+		ORDER BY fetched_at DESC
+		LIMIT 1`
+
+	var data []byte
+	var fetchedAt time.Time
+	err := r.db.QueryRowContext(ctx, query, fideID, ratingDate).Scan(&data, &fetchedAt)
+	if err == sql.ErrNoRows {
+		return nil, time.Time{}, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("query stale player cache by fide id: %w", err)
+	}
+
+	var player model.PlayerInfo
+	if err := json.Unmarshal(data, &player); err != nil {
+		return nil, time.Time{}, fmt.Errorf("unmarshal player data: %w", err)
+	}
+	setCached(&player, fetchedAt)
+
+	return &player, fetchedAt, nil
+}
+
 // GetBatch retrieves multiple cached players by member IDs and rating date
 func (r *PlayerRepository) GetBatch(ctx context.Context, memberIDs []int, ratingDate time.Time) (map[int]*model.PlayerInfo, error) {
 	if len(memberIDs) == 0 {
 		return make(map[int]*model.PlayerInfo), nil
 	}
 
+	result := make(map[int]*model.PlayerInfo)
+	var queryIDs []int
+	for _, memberID := range memberIDs {
+		if player, ok := r.memory.get(memberID, ratingDate); ok {
+			result[memberID] = player
+		} else {
+			queryIDs = append(queryIDs, memberID)
+		}
+	}
+	if len(queryIDs) == 0 {
+		return result, nil
+	}
+	gen := r.memory.generation()
+
 	query := `
-		SELECT member_id, data FROM player_cache
+		SELECT member_id, data, fetched_at, expires_at FROM player_cache
 		WHERE member_id = ANY($1) AND rating_date = $2
 		AND (expires_at IS NULL OR expires_at > NOW())`
 
-	rows, err := r.db.QueryContext(ctx, query, memberIDs, ratingDate)
+	rows, err := r.db.QueryContext(ctx, query, queryIDs, ratingDate)
 	if err != nil {
 		return nil, fmt.Errorf("query player cache batch: %w", err)
 	}
 	defer rows.Close()
 
-	result := make(map[int]*model.PlayerInfo)
 	for rows.Next() {
 		var memberID int
 		var data []byte
-		if err := rows.Scan(&memberID, &data); err != nil {
+		var fetchedAt time.Time
+		var expiresAt *time.Time
+		if err := rows.Scan(&memberID, &data, &fetchedAt, &expiresAt); err != nil {
 			return nil, fmt.Errorf("scan row: %w", err)
 		}
 
@@ -97,6 +197,8 @@ func (r *PlayerRepository) GetBatch(ctx context.Context, memberIDs []int, rating
 		if err := json.Unmarshal(data, &player); err != nil {
 			return nil, fmt.Errorf("unmarshal player data: %w", err)
 		}
+		r.memory.put(player, ratingDate, fetchedAt, expiresAt, len(data), gen)
+		setCached(&player, fetchedAt)
 
 		result[memberID] = &player
 	}
@@ -107,7 +209,7 @@ func (r *PlayerRepository) GetBatch(ctx context.Context, memberIDs []int, rating
 // GetRatingHistory retrieves all cached player info for a player (all dates)
 func (r *PlayerRepository) GetRatingHistory(ctx context.Context, memberID int) ([]model.PlayerInfo, error) {
 	query := `
-		SELECT data FROM player_cache
+		SELECT data, fetched_at FROM player_cache
 		WHERE member_id = $1
 		AND (expires_at IS NULL OR expires_at > NOW())
 		ORDER BY rating_date DESC`
@@ -121,7 +223,8 @@ func (r *PlayerRepository) GetRatingHistory(ctx context.Context, memberID int) (
 	var result []model.PlayerInfo
 	for rows.Next() {
 		var data []byte
-		if err := rows.Scan(&data); err != nil {
+		var fetchedAt time.Time
+		if err := rows.Scan(&data, &fetchedAt); err != nil {
 			return nil, fmt.Errorf("scan row: %w", err)
 		}
 
@@ -129,6 +232,7 @@ func (r *PlayerRepository) GetRatingHistory(ctx context.Context, memberID int) (
 		if err := json.Unmarshal(data, &player); err != nil {
 			return nil, fmt.Errorf("unmarshal player data: %w", err)
 		}
+		setCached(&player, fetchedAt)
 
 		result = append(result, player)
 	}
@@ -142,22 +246,37 @@ func (r *PlayerRepository) GetRatingHistoryForDates(ctx context.Context, memberI
 		return make(map[string]*model.PlayerInfo), nil
 	}
 
+	result := make(map[string]*model.PlayerInfo)
+	var queryDates []time.Time
+	for _, date := range dates {
+		if player, ok := r.memory.get(memberID, date); ok {
+			result[date.Format("2006-01-02")] = player
+		} else {
+			queryDates = append(queryDates, date)
+		}
+	}
+	if len(queryDates) == 0 {
+		return result, nil
+	}
+	gen := r.memory.generation()
+
 	query := `
-		SELECT rating_date, data FROM player_cache
+		SELECT rating_date, data, fetched_at, expires_at FROM player_cache
 		WHERE member_id = $1 AND rating_date = ANY($2)
 		AND (expires_at IS NULL OR expires_at > NOW())`
 
-	rows, err := r.db.QueryContext(ctx, query, memberID, dates)
+	rows, err := r.db.QueryContext(ctx, query, memberID, queryDates)
 	if err != nil {
 		return nil, fmt.Errorf("query rating history for dates: %w", err)
 	}
 	defer rows.Close()
 
-	result := make(map[string]*model.PlayerInfo)
 	for rows.Next() {
 		var ratingDate time.Time
 		var data []byte
-		if err := rows.Scan(&ratingDate, &data); err != nil {
+		var fetchedAt time.Time
+		var expiresAt *time.Time
+		if err := rows.Scan(&ratingDate, &data, &fetchedAt, &expiresAt); err != nil {
 			return nil, fmt.Errorf("scan row: %w", err)
 		}
 
@@ -165,6 +284,8 @@ func (r *PlayerRepository) GetRatingHistoryForDates(ctx context.Context, memberI
 		if err := json.Unmarshal(data, &player); err != nil {
 			return nil, fmt.Errorf("unmarshal player data: %w", err)
 		}
+		r.memory.put(player, ratingDate, fetchedAt, expiresAt, len(data), gen)
+		setCached(&player, fetchedAt)
 
 		dateStr := ratingDate.Format("2006-01-02")
 		result[dateStr] = &player
@@ -173,9 +294,76 @@ func (r *PlayerRepository) GetRatingHistoryForDates(ctx context.Context, memberI
 	return result, rows.Err()
 }
 
+// List returns all cache entries for a member, including expired ones,
+// newest rating date first
+func (r *PlayerRepository) List(ctx context.Context, memberID int) ([]model.CachedPlayer, error) {
+	query := `
+		SELECT rating_date, fetched_at, expires_at,
+			expires_at IS NOT NULL AND expires_at <= NOW(), data
+		FROM player_cache
+		WHERE member_id = $1
+		ORDER BY rating_date DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, memberID)
+	if err != nil {
+		return nil, fmt.Errorf("query player cache entries: %w", err)
+	}
+	defer rows.Close()
+
+	result := []model.CachedPlayer{}
+	for rows.Next() {
+		var entry model.CachedPlayer
+		var ratingDate time.Time
+		if err := rows.Scan(&ratingDate, &entry.FetchedAt, &entry.ExpiresAt, &entry.Expired, &entry.Data); err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		entry.RatingDate = ratingDate.Format("2006-01-02")
+		result = append(result, entry)
+	}
+
+	return result, rows.Err()
+}
+
+// Delete removes all cache entries for a member
+func (r *PlayerRepository) Delete(ctx context.Context, memberID int) (int64, error) {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM player_cache WHERE member_id = $1`, memberID)
+	r.memory.removeMember(memberID)
+	if err != nil {
+		return 0, fmt.Errorf("delete player cache: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// DeleteDate removes the cache entry for a member and rating date
+func (r *PlayerRepository) DeleteDate(ctx context.Context, memberID int, ratingDate time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM player_cache WHERE member_id = $1 AND rating_date = $2`, memberID, ratingDate)
+	r.memory.remove(memberID, ratingDate)
+	if err != nil {
+		return 0, fmt.Errorf("delete player cache: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// ClearMemory drops all players from the in-memory cache, e.g. after
+// player_cache was purged
+func (r *PlayerRepository) ClearMemory() {
+	r.memory.clear()
+}
+
+// setCached marks a player as served from a cache entry fetched at fetchedAt
+func setCached(player *model.PlayerInfo, fetchedAt time.Time) {
+	player.Cached = true
+	player.CachedAt = &fetchedAt
+}
+
 // Save stores a player in the cache
 func (r *PlayerRepository) Save(ctx context.Context, player *model.PlayerInfo, ratingDate time.Time, expiresAt *time.Time) error {
-	data, err := json.Marshal(player)
+	// The cache metadata is set on read, not stored
+	stored := *player
+	stored.Cached, stored.CachedAt = false, nil
+	data, err := json.Marshal(stored)
 	if err != nil {
 		return fmt.Errorf("marshal player data: %w", err)
 	}
@@ -217,25 +405,18 @@ func (r *PlayerRepository) Save(ctx context.Context, player *model.PlayerInfo, r
 			fetched_at = EXCLUDED.fetched_at,
 			expires_at = EXCLUDED.expires_at`
 
+	fetchedAt := time.Now()
+	gen := r.memory.generation()
 	_, err = r.db.ExecContext(ctx, query,
 		player.ID, ratingDate, player.FirstName, player.LastName,
 		player.Club, clubID, fideID,
 		eloStandard, eloRapid, eloBlitz, laskRating,
-		data, time.Now(), expiresAt)
+		data, fetchedAt, expiresAt)
 
 	if err != nil {
 		return fmt.Errorf("insert player cache: %w", err)
 	}
+	r.memory.put(stored, ratingDate, fetchedAt, expiresAt, len(data), gen)
 
 	return nil
 }
-
-// DeleteExpired removes expired cache entries
-func (r *PlayerRepository) DeleteExpired(ctx context.Context) (int64, error) {
-	result, err := r.db.ExecContext(ctx,
-		`DELETE FROM player_cache WHERE expires_at IS NOT NULL AND expires_at < NOW()`)
-	if err != nil {
-		return 0, fmt.Errorf("delete expired cache: %w", err)
-	}
-	return result.RowsAffected()
-}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/msvens/mchess/internal/metrics"
+	"github.com/msvens/mchess/internal/model"
+)
+
+var (
+	memoryLookups = metrics.NewCounterVec(
+		"mchess_cache_memory_lookups_total",
+		"Total number of player lookups in the in-memory cache, by result (hit, miss or expired).",
+		"result",
+	)
+	memoryEvictions = metrics.NewCounterVec(
+		"mchess_cache_memory_evictions_total",
+		"Total number of players evicted from the in-memory cache to stay within its limits.",
+	)
+)
+
+// memoryEntryOverhead approximates the memory an entry uses besides its
+// JSON data: the decoded struct, list element and map entry
+const memoryEntryOverhead = 512
+
+// memoryKey identifies a player cache entry
+type memoryKey struct {
+	memberID   int
+	ratingDate string // YYYY-MM-DD
+}
+
+func newMemoryKey(memberID int, ratingDate time.Time) memoryKey {
+	return memoryKey{memberID: memberID, ratingDate: ratingDate.Format("2006-01-02")}
+}
+
+type memoryEntry struct {
+	key       memoryKey
+	player    model.PlayerInfo // With the cache metadata set
+	expiresAt *time.Time       // nil = never expires
+	size      int64
+}
+
+// playerMemory is a bounded LRU cache of player cache entries, kept in
+// front of player_cache to save a query and a JSON decode per hit. Entries
+// expire with their expires_at, so historical months stay until evicted.
+// A nil playerMemory is disabled.
+//
+// Entries are put after the database is read or written, so a removal in
+// between could be undone. Callers take the generation before going to the
+// database and pass it to put, which skips the entry if anything was
+// removed since.
+type playerMemory struct {
+	mu         sync.Mutex
+	maxEntries int
+	maxBytes   int64 // 0 = no memory limit
+	bytes      int64
+	order      *list.List // Most recently used first
+	entries    map[memoryKey]*list.Element
+	gen        uint64 // Incremented by every remove, removeMember and clear
+}
+
+func newPlayerMemory(maxEntries int, maxBytes int64) *playerMemory {
+	return &playerMemory{
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		order:      list.New(),
+		entries:    make(map[memoryKey]*list.Element),
+	}
+}
+
+// get returns a copy of the cached player, unless it is missing or expired
+func (m *playerMemory) get(memberID int, ratingDate time.Time) (*model.PlayerInfo, bool) {
+	if m == nil {
+		return nil, false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	elem, ok := m.entries[newMemoryKey(memberID, ratingDate)]
+	if !ok {
+		memoryLookups.Inc("miss")
+		return nil, false
+	}
+	entry := elem.Value.(*memoryEntry)
+	if entry.expiresAt != nil && !time.Now().Before(*entry.expiresAt) {
+		m.removeElement(elem)
+		memoryLookups.Inc("expired")
+		return nil, false
+	}
+
+	m.order.MoveToFront(elem)
+	memoryLookups.Inc("hit")
+	player := entry.player
+	return &player, true
+}
+
+// generation returns the current generation, to pass to put
+func (m *playerMemory) generation() uint64 {
+	if m == nil {
+		return 0
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.gen
+}
+
+// put stores a player fetched at fetchedAt, evicting the least recently
+// used entries to stay within the limits. dataSize is the size of its JSON.
+// The player is not stored if entries were removed since generation gen.
+func (m *playerMemory) put(player model.PlayerInfo, ratingDate, fetchedAt time.Time, expiresAt *time.Time, dataSize int, gen uint64) {
+	if m == nil {
+		return
+	}
+	setCached(&player, fetchedAt)
+	entry := &memoryEntry{
+		key:       newMemoryKey(player.ID, ratingDate),
+		player:    player,
+		expiresAt: expiresAt,
+		size:      int64(dataSize) + memoryEntryOverhead,
+	}
+	if m.maxBytes > 0 && entry.size > m.maxBytes {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.gen != gen {
+		return
+	}
+	if elem, ok := m.entries[entry.key]; ok {
+		m.removeElement(elem)
+	}
+	m.entries[entry.key] = m.order.PushFront(entry)
+	m.bytes += entry.size
+
+	for m.order.Len() > m.maxEntries || (m.maxBytes > 0 && m.bytes > m.maxBytes) {
+		m.removeElement(m.order.Back())
+		memoryEvictions.Inc()
+	}
+}
+
+// remove drops the entry for a member and rating date
+func (m *playerMemory) remove(memberID int, ratingDate time.Time) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.gen++
+	if elem, ok := m.entries[newMemoryKey(memberID, ratingDate)]; ok {
+		m.removeElement(elem)
+	}
+}
+
+// removeMember drops all entries for a member
+func (m *playerMemory) removeMember(memberID int) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.gen++
+	for key, elem := range m.entries {
+		if key.memberID == memberID {
+			m.removeElement(elem)
+		}
+	}
+}
+
+// clear drops all entries
+func (m *playerMemory) clear() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.gen++
+	m.order.Init()
+	m.entries = make(map[memoryKey]*list.Element)
+	m.bytes = 0
+}
+
+func (m *playerMemory) removeElement(elem *list.Element) {
+	entry := m.order.Remove(elem).(*memoryEntry)
+	delete(m.entries, entry.key)
+	m.bytes -= entry.size
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/msvens/mchess/internal/model"
+)
+
+func TestPlayerMemory(t *testing.T) {
+	june := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	fetchedAt := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	player := func(id int) model.PlayerInfo { return model.PlayerInfo{ID: id, FirstName: "Anna"} }
+
+	t.Run("Hit_SetsCacheMetadata", func(t *testing.T) {
+		m := newPlayerMemory(10, 0)
+		m.put(player(1), june, fetchedAt, nil, 100, m.generation())
+
+		got, ok := m.get(1, june)
+		if !ok {
+			t.Fatal("get: got miss, want hit")
+		}
+		if !got.Cached || got.CachedAt == nil || !got.CachedAt.Equal(fetchedAt) {
+			t.Errorf("Cache metadata: got %v %v, want true %v", got.Cached, got.CachedAt, fetchedAt)
+		}
+		if _, ok := m.get(1, june.AddDate(0, -1, 0)); ok {
+			t.Error("get other rating date: got hit, want miss")
+		}
+	})
+
+	t.Run("Expired_Miss", func(t *testing.T) {
+		m := newPlayerMemory(10, 0)
+		expired := time.Now().Add(-time.Minute)
+		m.put(player(1), june, fetchedAt, &expired, 100, m.generation())
+
+		if _, ok := m.get(1, june); ok {
+			t.Error("get: got hit, want miss for expired entry")
+		}
+		if m.order.Len() != 0 {
+			t.Errorf("Entries: got %d, want expired entry removed", m.order.Len())
+		}
+	})
+
+	t.Run("MaxEntries_EvictsLeastRecentlyUsed", func(t *testing.T) {
+		m := newPlayerMemory(2, 0)
+		m.put(player(1), june, fetchedAt, nil, 100, m.generation())
+		m.put(player(2), june, fetchedAt, nil, 100, m.generation())
+		m.get(1, june)
+		m.put(player(3), june, fetchedAt, nil, 100, m.generation())
+
+		for id, want := range map[int]bool{1: true, 2: false, 3: true} {
+			if _, ok := m.get(id, june); ok != want {
+				t.Errorf("get %d: got %v, want %v", id, ok, want)
+			}
+		}
+	})
+
+	t.Run("MaxBytes_Evicts", func(t *testing.T) {
+		m := newPlayerMemory(10, 2*(memoryEntryOverhead+100))
+		for id := 1; id <= 3; id++ {
+			m.put(player(id), june, fetchedAt, nil, 100, m.generation())
+		}
+		if m.order.Len() != 2 || m.bytes > m.maxBytes {
+			t.Errorf("Entries: got %d using %d bytes, want 2 within %d", m.order.Len(), m.bytes, m.maxBytes)
+		}
+	})
+
+	t.Run("RemoveMember", func(t *testing.T) {
+		m := newPlayerMemory(10, 0)
+		m.put(player(1), june, fetchedAt, nil, 100, m.generation())
+		m.put(player(1), june.AddDate(0, -1, 0), fetchedAt, nil, 100, m.generation())
+		m.put(player(2), june, fetchedAt, nil, 100, m.generation())
+		m.removeMember(1)
+
+		if m.order.Len() != 1 {
+			t.Errorf("Entries: got %d, want 1", m.order.Len())
+		}
+		if _, ok := m.get(2, june); !ok {
+			t.Error("get 2: got miss, want hit")
+		}
+	})
+
+	t.Run("RemovedDuringRead_PutSkipped", func(t *testing.T) {
+		removals := map[string]func(m *playerMemory){
+			"remove":       func(m *playerMemory) { m.remove(1, june) },
+			"removeMember": func(m *playerMemory) { m.removeMember(1) },
+			"clear":        func(m *playerMemory) { m.clear() },
+		}
+		for name, remove := range removals {
+			m := newPlayerMemory(10, 0)
+			m.put(player(1), june, fetchedAt, nil, 100, m.generation())
+
+			// A read of the database races with an admin delete
+			gen := m.generation()
+			remove(m)
+			m.put(player(1), june, fetchedAt, nil, 100, gen)
+			if _, ok := m.get(1, june); ok {
+				t.Errorf("%s: get after put from before the removal: got hit, want miss", name)
+			}
+
+			m.put(player(1), june, fetchedAt, nil, 100, m.generation())
+			if _, ok := m.get(1, june); !ok {
+				t.Errorf("%s: get after put from after the removal: got miss, want hit", name)
+			}
+		}
+	})
+
+	t.Run("Disabled_NilIsSafe", func(t *testing.T) {
+		var m *playerMemory
+		m.put(player(1), june, fetchedAt, nil, 100, m.generation())
+		if _, ok := m.get(1, june); ok {
+			t.Error("get: got hit from disabled cache")
+		}
+		m.remove(1, june)
+		m.clear()
+	})
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Rating list scopes
+const (
+	RatingListScopeFederation = "federation"
+	RatingListScopeDistrict   = "district"
+	RatingListScopeClub       = "club"
+)
+
+// RatingListKey identifies a rating list snapshot
+type RatingListKey struct {
+	Scope      string
+	ScopeID    int       // District or club ID, 0 for federation
+	RatingDate time.Time // First of month
+	RatingType int
+	Category   int
+}
+
+// RatingListRepository handles rating list snapshot database operations
+type RatingListRepository struct {
+	db *sql.DB
+}
+
+// NewRatingListRepository creates a new rating list repository
+func NewRatingListRepository(db *sql.DB) *RatingListRepository {
+	return &RatingListRepository{db: db}
+}
+
+// Get retrieves a cached rating list snapshot as the stored upstream JSON.
+// Returns the time the snapshot was fetched, or nil if not cached.
+func (r *RatingListRepository) Get(ctx context.Context, key RatingListKey) ([]byte, time.Time, error) {
+	query := `
+		SELECT data, fetched_at FROM rating_list_snapshot
+		WHERE scope = $1 AND scope_id = $2 AND rating_date = $3
+		AND rating_type = $4 AND category = $5
+		AND (expires_at IS NULL OR expires_at > NOW())`
+
+	var data []byte
+	var fetchedAt time.Time
+	err := r.db.QueryRowContext(ctx, query,
+		key.Scope, key.ScopeID, key.RatingDate, key.RatingType, key.Category).Scan(&data, &fetchedAt)
+	if err == sql.ErrNoRows {
+		return nil, time.Time{}, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("query rating list snapshot: %w", err)
+	}
+
+	return data, fetchedAt, nil
+}
+
+// GetStale retrieves a rating list snapshot including expired entries, as
+// the stored upstream JSON. Returns the time the snapshot was fetched, or
+// nil if not cached.
+func (r *RatingListRepository) GetStale(ctx context.Context, key RatingListKey) ([]byte, time.Time, error) {
+	query := `
+		SELECT data, fetched_at FROM rating_list_snapshot
+		WHERE scope = $1 AND scope_id = $2 AND rating_date = $3
+		AND rating_type = $4 AND category = $5`
+
+	var data []byte
+	var fetchedAt time.Time
+	err := r.db.QueryRowContext(ctx, query,
+		key.Scope, key.ScopeID, key.RatingDate, key.RatingType, key.Category).Scan(&data, &fetchedAt)
+	if err == sql.ErrNoRows {
+		return nil, time.Time{}, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("query stale rating list snapshot: %w", err)
+	}
+
+	return data, fetchedAt, nil
+}
+
+// Save stores a rating list snapshot. data is the upstream JSON, stored as
+// is so fields missing from model.PlayerInfo are kept.
+func (r *RatingListRepository) Save(ctx context.Context, key RatingListKey, data []byte, expiresAt *time.Time) error {
+	query := `
+		INSERT INTO rating_list_snapshot (
+			scope, scope_id, rating_date, rating_type, category, data, fetched_at, expires_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (scope, scope_id, rating_date, rating_type, category) DO UPDATE SET
+			data = EXCLUDED.data,
+			fetched_at = EXCLUDED.fetched_at,
+			expires_at = EXCLUDED.expires_at`
+
+	_, err := r.db.ExecContext(ctx, query,
+		key.Scope, key.ScopeID, key.RatingDate, key.RatingType, key.Category,
+		data, time.Now(), expiresAt)
+	if err != nil {
+		return fmt.Errorf("insert rating list snapshot: %w", err)
+	}
+
+	return nil
+}
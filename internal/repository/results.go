@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Result types stored in the results cache, one per upstream results endpoint
+const (
+	ResultTypeTable            = "table"
+	ResultTypeRoundResults     = "roundresults"
+	ResultTypeTeamTable        = "teamtable"
+	ResultTypeTeamRoundResults = "teamroundresults"
+)
+
+// ResultsRepository handles tournament results cache database operations
+type ResultsRepository struct {
+	db *sql.DB
+}
+
+// NewResultsRepository creates a new results repository
+func NewResultsRepository(db *sql.DB) *ResultsRepository {
+	return &ResultsRepository{db: db}
+}
+
+// Get retrieves cached results for a group and decodes them into result.
+// Returns false if there is no valid cache entry, and otherwise the time it was fetched.
+func (r *ResultsRepository) Get(ctx context.Context, groupID int, resultType string, result interface{}) (bool, time.Time, error) {
+	query := `
+		SELECT data, fetched_at FROM results_cache
+		WHERE group_id = $1 AND result_type = $2
+		AND (expires_at IS NULL OR expires_at > NOW())`
+
+	var data []byte
+	var fetchedAt time.Time
+	err := r.db.QueryRowContext(ctx, query, groupID, resultType).Scan(&data, &fetchedAt)
+	if err == sql.ErrNoRows {
+		return false, time.Time{}, nil
+	}
+	if err != nil {
+		return false, time.Time{}, fmt.Errorf("query results cache: %w", err)
+	}
+
+	if err := json.Unmarshal(data, result); err != nil {
+		return false, time.Time{}, fmt.Errorf("unmarshal results data: %w", err)
+	}
+
+	return true, fetchedAt, nil
+}
+
+// GetStale retrieves cached results for a group including expired entries.
+// Returns false if there is no cache entry, and otherwise the time it was fetched.
+func (r *ResultsRepository) GetStale(ctx context.Context, groupID int, resultType string, result interface{}) (bool, time.Time, error) {
+	query := `
+		SELECT data, fetched_at FROM results_cache
+		WHERE group_id = $1 AND result_type = $2`
+
+	var data []byte
+	var fetchedAt time.Time
+	err := r.db.QueryRowContext(ctx, query, groupID, resultType).Scan(&data, &fetchedAt)
+	if err == sql.ErrNoRows {
+		return false, time.Time{}, nil
+	}
+	if err != nil {
+		return false, time.Time{}, fmt.Errorf("query stale results cache: %w", err)
+	}
+
+	if err := json.Unmarshal(data, result); err != nil {
+		return false, time.Time{}, fmt.Errorf("unmarshal results data: %w", err)
+	}
+
+	return true, fetchedAt, nil
+}
+
+// Save stores results for a group in the cache
+func (r *ResultsRepository) Save(ctx context.Context, groupID int, resultType string, results interface{}, expiresAt *time.Time) error {
+	data, err := json.Marshal(results)
+	if err != nil {
+		return fmt.Errorf("marshal results data: %w", err)
+	}
+
+	query := `
+		INSERT INTO results_cache (
+			group_id, result_type, data, fetched_at, expires_at
+		) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (group_id, result_type) DO UPDATE SET
+			data = EXCLUDED.data,
+			fetched_at = EXCLUDED.fetched_at,
+			expires_at = EXCLUDED.expires_at`
+
+	_, err = r.db.ExecContext(ctx, query, groupID, resultType, data, time.Now(), expiresAt)
+	if err != nil {
+		return fmt.Errorf("insert results cache: %w", err)
+	}
+
+	return nil
+}
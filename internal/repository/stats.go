@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/msvens/mchess/internal/model"
+)
+
+// StatsRepository handles cache_stats database operations
+type StatsRepository struct {
+	db *sql.DB
+}
+
+// NewStatsRepository creates a new stats repository
+func NewStatsRepository(db *sql.DB) *StatsRepository {
+	return &StatsRepository{db: db}
+}
+
+// Increment adds the given counts to a cache type, creating the row if needed
+func (r *StatsRepository) Increment(ctx context.Context, cacheType string, hits, misses, upstreamCalls int64) error {
+	query := `
+		INSERT INTO cache_stats (cache_type, hits, misses, upstream_calls)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (cache_type) DO UPDATE SET
+			hits = COALESCE(cache_stats.hits, 0) + EXCLUDED.hits,
+			misses = COALESCE(cache_stats.misses, 0) + EXCLUDED.misses,
+			upstream_calls = COALESCE(cache_stats.upstream_calls, 0) + EXCLUDED.upstream_calls`
+
+	_, err := r.db.ExecContext(ctx, query, cacheType, hits, misses, upstreamCalls)
+	if err != nil {
+		return fmt.Errorf("increment cache stats: %w", err)
+	}
+	return nil
+}
+
+// List returns the stored statistics for all cache types
+func (r *StatsRepository) List(ctx context.Context) ([]model.CacheStats, error) {
+	query := `
+		SELECT cache_type, COALESCE(hits, 0), COALESCE(misses, 0),
+			COALESCE(upstream_calls, 0), COALESCE(last_reset, NOW())
+		FROM cache_stats
+		ORDER BY cache_type`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query cache stats: %w", err)
+	}
+	defer rows.Close()
+
+	var result []model.CacheStats
+	for rows.Next() {
+		var stats model.CacheStats
+		if err := rows.Scan(&stats.CacheType, &stats.Hits, &stats.Misses,
+			&stats.UpstreamCalls, &stats.LastReset); err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		result = append(result, stats)
+	}
+
+	return result, rows.Err()
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/msvens/mchess/internal/model"
+)
+
+// Tournament lookup types - the upstream API can resolve a tournament from
+// its own ID, a group ID or a class ID
+const (
+	TournamentLookupTournament = "tournament"
+	TournamentLookupGroup      = "group"
+	TournamentLookupClass      = "class"
+)
+
+// TournamentRepository handles tournament cache database operations
+type TournamentRepository struct {
+	db *sql.DB
+}
+
+// NewTournamentRepository creates a new tournament repository
+func NewTournamentRepository(db *sql.DB) *TournamentRepository {
+	return &TournamentRepository{db: db}
+}
+
+// Get retrieves a cached tournament by lookup type and ID as the stored
+// upstream JSON. Returns the time the entry was fetched, or nil if not cached.
+func (r *TournamentRepository) Get(ctx context.Context, lookupType string, lookupID int) ([]byte, time.Time, error) {
+	query := `
+		SELECT data, fetched_at FROM tournament_cache
+		WHERE lookup_type = $1 AND lookup_id = $2
+		AND (expires_at IS NULL OR expires_at > NOW())`
+
+	var data []byte
+	var fetchedAt time.Time
+	err := r.db.QueryRowContext(ctx, query, lookupType, lookupID).Scan(&data, &fetchedAt)
+	if err == sql.ErrNoRows {
+		return nil, time.Time{}, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("query tournament cache: %w", err)
+	}
+
+	return data, fetchedAt, nil
+}
+
+// GetStale retrieves a cached tournament including expired entries, as the
+// stored upstream JSON. Returns the time the entry was fetched, or nil if
+// not cached.
+func (r *TournamentRepository) GetStale(ctx context.Context, lookupType string, lookupID int) ([]byte, time.Time, error) {
+	query := `
+		SELECT data, fetched_at FROM tournament_cache
+		WHERE lookup_type = $1 AND lookup_id = $2`
+
+	var data []byte
+	var fetchedAt time.Time
+	err := r.db.QueryRowContext(ctx, query, lookupType, lookupID).Scan(&data, &fetchedAt)
+	if err == sql.ErrNoRows {
+		return nil, time.Time{}, nil
+	}
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("query stale tournament cache: %w", err)
+	}
+
+	return data, fetchedAt, nil
+}
+
+// Save stores a tournament in the cache. data is the upstream JSON, stored
+// as is so fields missing from model.Tournament are kept; tournament is its
+// decoded form, used for the indexed columns.
+func (r *TournamentRepository) Save(ctx context.Context, lookupType string, lookupID int, tournament *model.Tournament, data []byte, expiresAt *time.Time) error {
+	var start, end *time.Time
+	if tournament.Start != nil {
+		start = &tournament.Start.Time
+	}
+	if tournament.End != nil {
+		end = &tournament.End.Time
+	}
+
+	query := `
+		INSERT INTO tournament_cache (
+			lookup_type, lookup_id, tournament_id, name, start_date, end_date, state,
+			data, fetched_at, expires_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (lookup_type, lookup_id) DO UPDATE SET
+			tournament_id = EXCLUDED.tournament_id,
+			name = EXCLUDED.name,
+			start_date = EXCLUDED.start_date,
+			end_date = EXCLUDED.end_date,
+			state = EXCLUDED.state,
+			data = EXCLUDED.data,
+			fetched_at = EXCLUDED.fetched_at,
+			expires_at = EXCLUDED.expires_at`
+
+	_, err := r.db.ExecContext(ctx, query,
+		lookupType, lookupID, tournament.ID, tournament.Name,
+		start, end, tournament.State,
+		data, time.Now(), expiresAt)
+
+	if err != nil {
+		return fmt.Errorf("insert tournament cache: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+// Package schedule computes run times for background jobs from specs like
+// "daily 05:00", "weekly fri 18:00" or "monthly 1 04:00".
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a set of recurring times. The zero Schedule never runs.
+type Schedule struct {
+	entries []entry
+}
+
+// entry is one recurring time
+type entry struct {
+	kind   string // daily, weekly or monthly
+	day    int    // Weekday for weekly, day of month for monthly
+	hour   int
+	minute int
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// Parse parses schedule specs, one recurring time each:
+//
+//	daily HH:MM
+//	weekly <mon|tue|wed|thu|fri|sat|sun> HH:MM
+//	monthly <1-31> HH:MM
+//
+// Monthly days past the end of a month run on its last day.
+func Parse(specs []string) (Schedule, error) {
+	var s Schedule
+	for _, spec := range specs {
+		e, err := parseEntry(spec)
+		if err != nil {
+			return Schedule{}, fmt.Errorf("invalid schedule %q: %w", spec, err)
+		}
+		s.entries = append(s.entries, e)
+	}
+	return s, nil
+}
+
+func parseEntry(spec string) (entry, error) {
+	fields := strings.Fields(strings.ToLower(spec))
+	if len(fields) == 0 {
+		return entry{}, fmt.Errorf("empty")
+	}
+
+	e := entry{kind: fields[0]}
+	var clock string
+	switch {
+	case e.kind == "daily" && len(fields) == 2:
+		clock = fields[1]
+	case e.kind == "weekly" && len(fields) == 3:
+		weekday, ok := weekdays[fields[1]]
+		if !ok {
+			return entry{}, fmt.Errorf("unknown weekday %q", fields[1])
+		}
+		e.day = int(weekday)
+		clock = fields[2]
+	case e.kind == "monthly" && len(fields) == 3:
+		day, err := strconv.Atoi(fields[1])
+		if err != nil || day < 1 || day > 31 {
+			return entry{}, fmt.Errorf("day of month must be 1-31")
+		}
+		e.day = day
+		clock = fields[2]
+	default:
+		return entry{}, fmt.Errorf(`want "daily HH:MM", "weekly <weekday> HH:MM" or "monthly <day> HH:MM"`)
+	}
+
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return entry{}, fmt.Errorf("time must be HH:MM")
+	}
+	e.hour, e.minute = t.Hour(), t.Minute()
+	return e, nil
+}
+
+// Empty reports whether the schedule never runs
+func (s Schedule) Empty() bool {
+	return len(s.entries) == 0
+}
+
+// Next returns the first scheduled time after t, in t's location, or the
+// zero time if the schedule is empty
+func (s Schedule) Next(t time.Time) time.Time {
+	var next time.Time
+	for _, e := range s.entries {
+		if n := e.next(t); next.IsZero() || n.Before(next) {
+			next = n
+		}
+	}
+	return next
+}
+
+func (e entry) next(t time.Time) time.Time {
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, e.hour, e.minute, 0, 0, t.Location())
+	}
+
+	switch e.kind {
+	case "daily":
+		n := at(t.Year(), t.Month(), t.Day())
+		if !n.After(t) {
+			n = at(t.Year(), t.Month(), t.Day()+1)
+		}
+		return n
+	case "weekly":
+		days := (e.day - int(t.Weekday()) + 7) % 7
+		n := at(t.Year(), t.Month(), t.Day()+days)
+		if !n.After(t) {
+			n = at(t.Year(), t.Month(), t.Day()+days+7)
+		}
+		return n
+	default: // monthly
+		for i := 0; ; i++ {
+			first := time.Date(t.Year(), t.Month()+time.Month(i), 1, 0, 0, 0, 0, t.Location())
+			lastDay := first.AddDate(0, 1, -1).Day()
+			n := at(first.Year(), first.Month(), min(e.day, lastDay))
+			if n.After(t) {
+				return n
+			}
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/msvens/mchess/internal/schedule"
+)
+
+func TestNext(t *testing.T) {
+	// A Wednesday
+	now := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		specs []string
+		want  time.Time
+	}{
+		{[]string{"daily 13:30"}, time.Date(2024, 1, 31, 13, 30, 0, 0, time.UTC)},
+		{[]string{"daily 12:00"}, time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)},
+		{[]string{"weekly fri 18:00"}, time.Date(2024, 2, 2, 18, 0, 0, 0, time.UTC)},
+		{[]string{"weekly wed 11:00"}, time.Date(2024, 2, 7, 11, 0, 0, 0, time.UTC)},
+		{[]string{"monthly 1 04:00"}, time.Date(2024, 2, 1, 4, 0, 0, 0, time.UTC)},
+		{[]string{"monthly 31 04:00"}, time.Date(2024, 2, 29, 4, 0, 0, 0, time.UTC)},
+		{[]string{"Monthly 31 18:00"}, time.Date(2024, 1, 31, 18, 0, 0, 0, time.UTC)},
+		{[]string{"weekly fri 18:00", "monthly 1 04:00"}, time.Date(2024, 2, 1, 4, 0, 0, 0, time.UTC)},
+		{nil, time.Time{}},
+	}
+	for _, tt := range tests {
+		s, err := schedule.Parse(tt.specs)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.specs, err)
+		}
+		if got := s.Next(now); !got.Equal(tt.want) {
+			t.Errorf("Next(%q) = %v, want %v", tt.specs, got, tt.want)
+		}
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, spec := range []string{"", "hourly", "daily", "daily 25:00", "weekly someday 10:00", "monthly 0 04:00", "monthly 1"} {
+		if _, err := schedule.Parse([]string{spec}); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", spec)
+		}
+	}
+}
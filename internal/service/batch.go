@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/msvens/mchess/internal/config"
+	"github.com/msvens/mchess/internal/model"
+)
+
+// ErrBatchDeadline is reported for batch items that were not fetched
+// before the request deadline
+var ErrBatchDeadline = errors.New("not fetched before request deadline")
+
+// runBatch calls fetch for every item using at most cfg.Workers goroutines,
+// and returns the results and errors by item index.
+//
+// Each call gets its own cfg.ItemTimeout. If ctx has a deadline, the batch
+// stops cfg.DeadlineMargin before it so there is time left to respond with
+// partial results: calls in flight are cancelled, and they and the items
+// that were never started fail with ErrBatchDeadline.
+func runBatch[T, R any](ctx context.Context, cfg config.BatchConfig, items []T,
+	fetch func(ctx context.Context, item T) (R, error)) ([]R, []error) {
+
+	results := make([]R, len(items))
+	errs := make([]error, len(items))
+	for i := range errs {
+		errs[i] = ErrBatchDeadline
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline.Add(-cfg.DeadlineMargin))
+		defer cancel()
+	}
+
+	workers := min(max(cfg.Workers, 1), len(items))
+	next := make(chan int)
+	var wg sync.WaitGroup
+
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i], errs[i] = fetchItem(ctx, cfg.ItemTimeout, items[i], fetch)
+				if errs[i] != nil && ctx.Err() != nil {
+					// Cancelled by the batch deadline rather than failed on its own
+					errs[i] = ErrBatchDeadline
+				}
+			}
+		}()
+	}
+
+feed:
+	for i := range items {
+		select {
+		case <-ctx.Done():
+			break feed
+		case next <- i:
+		}
+	}
+	close(next)
+	wg.Wait()
+
+	return results, errs
+}
+
+// fetchItem calls fetch for a single item with its own timeout
+func fetchItem[T, R any](ctx context.Context, timeout time.Duration, item T,
+	fetch func(ctx context.Context, item T) (R, error)) (R, error) {
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return fetch(ctx, item)
+}
+
+// getBatch looks up every ID with get using runBatch, and returns the results
+// found in input order together with an error for each ID that failed
+func getBatch[R any](ctx context.Context, cfg config.BatchConfig, cacheType string, ids []int,
+	get func(ctx context.Context, id int) (R, error)) ([]R, []model.BatchError) {
+
+	results, errs := runBatch(ctx, cfg, ids, get)
+
+	found := make([]R, 0, len(ids))
+	var failed []model.BatchError
+	for i, id := range ids {
+		if errs[i] != nil {
+			slog.Warn("Batch lookup failed", "type", cacheType, "id", id, "error", errs[i])
+			failed = append(failed, model.BatchError{ID: id, Error: errs[i].Error()})
+			continue
+		}
+		found = append(found, results[i])
+	}
+
+	return found, failed
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/msvens/mchess/internal/config"
+)
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestRunBatch(t *testing.T) {
+	items := make([]int, 12)
+	for i := range items {
+		items[i] = i
+	}
+
+	t.Run("LimitsWorkers", func(t *testing.T) {
+		var running, peak atomic.Int32
+		cfg := config.BatchConfig{Workers: 3}
+
+		results, errs := runBatch(context.Background(), cfg, items, func(ctx context.Context, item int) (int, error) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			return item * 2, nil
+		})
+
+		if p := peak.Load(); p != 3 {
+			t.Errorf("Concurrent fetches: got %d, want 3", p)
+		}
+		for i := range items {
+			if errs[i] != nil || results[i] != i*2 {
+				t.Errorf("Item %d: got %d, %v, want %d", i, results[i], errs[i], i*2)
+			}
+		}
+	})
+
+	t.Run("DeadlineMargin_UnreachedItemsFail", func(t *testing.T) {
+		// The batch stops 100ms into the request, after the first item
+		cfg := config.BatchConfig{Workers: 1, DeadlineMargin: 200 * time.Millisecond}
+		ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, errs := runBatch(ctx, cfg, items, func(ctx context.Context, item int) (int, error) {
+			return item, sleep(ctx, 60*time.Millisecond)
+		})
+
+		if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+			t.Errorf("Elapsed: got %v, want the batch to stop before the margin", elapsed)
+		}
+		if errs[0] != nil {
+			t.Errorf("Item 0: got %v, want success", errs[0])
+		}
+		for i := 1; i < len(items); i++ {
+			if !errors.Is(errs[i], ErrBatchDeadline) {
+				t.Errorf("Item %d: got %v, want ErrBatchDeadline", i, errs[i])
+			}
+		}
+	})
+
+	t.Run("ItemTimeout_KeepsError", func(t *testing.T) {
+		cfg := config.BatchConfig{Workers: 2, ItemTimeout: 20 * time.Millisecond, DeadlineMargin: time.Second}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		_, errs := runBatch(ctx, cfg, items, func(ctx context.Context, item int) (int, error) {
+			if item == 1 {
+				return 0, sleep(ctx, time.Second)
+			}
+			return item, nil
+		})
+
+		if !errors.Is(errs[1], context.DeadlineExceeded) || errors.Is(errs[1], ErrBatchDeadline) {
+			t.Errorf("Item 1: got %v, want its own DeadlineExceeded", errs[1])
+		}
+		for i := range items {
+			if i != 1 && errs[i] != nil {
+				t.Errorf("Item %d: got %v, want success", i, errs[i])
+			}
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/msvens/mchess/internal/model"
+	"github.com/msvens/mchess/internal/repository"
+)
+
+// CacheAdminService inspects and invalidates cache entries
+type CacheAdminService struct {
+	cache   *repository.CacheRepository
+	players *repository.PlayerRepository
+}
+
+// NewCacheAdminService creates a new cache admin service
+func NewCacheAdminService(cache *repository.CacheRepository, players *repository.PlayerRepository) *CacheAdminService {
+	return &CacheAdminService{cache: cache, players: players}
+}
+
+// CacheTypes returns the cache types that can be purged
+func (s *CacheAdminService) CacheTypes() []string {
+	return repository.CacheTypes()
+}
+
+// ListPlayer returns all cache entries for a member, including expired ones
+func (s *CacheAdminService) ListPlayer(ctx context.Context, memberID int) (*model.PlayerCacheResponse, error) {
+	entries, err := s.players.List(ctx, memberID)
+	if err != nil {
+		return nil, err
+	}
+	return &model.PlayerCacheResponse{MemberID: memberID, Entries: entries}, nil
+}
+
+// DeletePlayer removes all cache entries for a member
+func (s *CacheAdminService) DeletePlayer(ctx context.Context, memberID int) (int64, error) {
+	return s.players.Delete(ctx, memberID)
+}
+
+// DeletePlayerDate removes the cache entry for a member and the month of date
+func (s *CacheAdminService) DeletePlayerDate(ctx context.Context, memberID int, date time.Time) (int64, error) {
+	return s.players.DeleteDate(ctx, memberID, normalizeToMonthStart(date))
+}
+
+// Purge removes the entries of the given cache types that were fetched
+// before fetchedBefore, or all of them if fetchedBefore is zero
+func (s *CacheAdminService) Purge(ctx context.Context, cacheTypes []string, fetchedBefore time.Time) (int64, error) {
+	var deleted int64
+	for _, cacheType := range cacheTypes {
+		n, err := s.cache.Purge(ctx, cacheType, fetchedBefore)
+		if cacheType == CacheTypePlayer {
+			// Memory entries are cheap to reload from the database, drop them all
+			s.players.ClearMemory()
+		}
+		if err != nil {
+			return deleted, err
+		}
+		deleted += n
+	}
+	return deleted, nil
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/msvens/mchess/internal/metrics"
+)
+
+var cacheStaleServed = metrics.NewCounterVec(
+	"mchess_cache_stale_served_total",
+	"Total number of expired cache entries served because upstream was unavailable, by cache type.",
+	"cache",
+)
+
+// Cache status of a response, as reported in the X-Cache header
+const (
+	CacheHit   = "HIT"   // Every entry was served from the cache
+	CacheMiss  = "MISS"  // At least one entry was fetched from upstream
+	CacheStale = "STALE" // At least one expired entry was served because upstream was unavailable
+)
+
+type cacheStatusKey struct{}
+
+// CacheStatus records how the cache entries of a response were served:
+// from the cache, from upstream, or from expired entries because upstream
+// was unavailable. It is safe for concurrent use.
+type CacheStatus struct {
+	mu        sync.Mutex
+	hit       bool
+	miss      bool
+	stale     bool
+	fetchedAt time.Time // Fetch time of the oldest cached entry served
+	staleAt   time.Time // Fetch time of the oldest stale entry served
+}
+
+// WithCacheStatus returns a context that records cache use into the returned CacheStatus
+func WithCacheStatus(ctx context.Context) (context.Context, *CacheStatus) {
+	s := &CacheStatus{}
+	return context.WithValue(ctx, cacheStatusKey{}, s), s
+}
+
+// Status returns CacheStale, CacheMiss or CacheHit, in that order of
+// precedence, or "" if the cache wasn't used. fetchedAt is the fetch time of
+// the oldest entry served from the cache, or zero if there was none.
+func (s *CacheStatus) Status() (status string, fetchedAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case s.stale:
+		return CacheStale, s.fetchedAt
+	case s.miss:
+		return CacheMiss, s.fetchedAt
+	case s.hit:
+		return CacheHit, s.fetchedAt
+	}
+	return "", time.Time{}
+}
+
+// Stale reports whether any stale entry was served, and when the oldest one was fetched
+func (s *CacheStatus) Stale() (bool, time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stale, s.staleAt
+}
+
+// CacheStatusFromContext returns the CacheStatus recording into ctx, if any
+func CacheStatusFromContext(ctx context.Context) (*CacheStatus, bool) {
+	s, ok := ctx.Value(cacheStatusKey{}).(*CacheStatus)
+	return s, ok
+}
+
+// RecordHit records that an entry fetched at fetchedAt was served from the cache
+func (s *CacheStatus) RecordHit(fetchedAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hit = true
+	s.served(fetchedAt)
+}
+
+// RecordMiss records that an entry was not in the cache
+func (s *CacheStatus) RecordMiss() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.miss = true
+}
+
+// RecordStale records that an expired entry fetched at fetchedAt was served
+// because upstream was unavailable
+func (s *CacheStatus) RecordStale(fetchedAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.stale || fetchedAt.Before(s.staleAt) {
+		s.staleAt = fetchedAt
+	}
+	s.stale = true
+	s.served(fetchedAt)
+}
+
+// cacheHit records that an entry fetched at fetchedAt was served from the cache
+func cacheHit(ctx context.Context, fetchedAt time.Time) {
+	if s, ok := CacheStatusFromContext(ctx); ok {
+		s.RecordHit(fetchedAt)
+	}
+}
+
+// cacheMiss records that an entry was not in the cache
+func cacheMiss(ctx context.Context) {
+	if s, ok := CacheStatusFromContext(ctx); ok {
+		s.RecordMiss()
+	}
+}
+
+// serveStale records that an entry fetched at fetchedAt is served in place
+// of the upstream error err. args are extra log attributes identifying the entry.
+func serveStale(ctx context.Context, cacheType string, fetchedAt time.Time, err error, args ...any) {
+	args = append([]any{"cache", cacheType, "fetchedAt", fetchedAt, "error", err}, args...)
+	slog.Warn("Upstream unavailable, serving stale cache", args...)
+	cacheStaleServed.Inc(cacheType)
+
+	if s, ok := CacheStatusFromContext(ctx); ok {
+		s.RecordStale(fetchedAt)
+	}
+}
+
+// served keeps the oldest fetch time of the entries served from the cache.
+// Unknown (zero) fetch times are ignored. s.mu must be held.
+func (s *CacheStatus) served(fetchedAt time.Time) {
+	if fetchedAt.IsZero() {
+		return
+	}
+	if s.fetchedAt.IsZero() || fetchedAt.Before(s.fetchedAt) {
+		s.fetchedAt = fetchedAt
+	}
+}
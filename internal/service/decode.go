@@ -0,0 +1,23 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/msvens/mchess/internal/upstream"
+)
+
+// decodeUpstream decodes upstream JSON returned with err by a raw lookup.
+// Services cache and serve the upstream JSON as is, so fields the model
+// does not know about are passed through, and decode it where they need
+// the values.
+func decodeUpstream[T any](data []byte, err error) (T, error) {
+	var value T
+	if err != nil {
+		return value, err
+	}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return value, fmt.Errorf("decode response: %w: %w", upstream.ErrInvalidResponse, err)
+	}
+	return value, nil
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/msvens/mchess/internal/config"
+	"github.com/msvens/mchess/internal/metrics"
+	"github.com/msvens/mchess/internal/repository"
+)
+
+var cacheExpiredDeleted = metrics.NewCounterVec(
+	"mchess_cache_expired_deleted_total",
+	"Total number of expired cache entries deleted by the cache janitor, by cache type.",
+	"cache",
+)
+
+// defaultPurgeBatchSize is used when cache.purgeBatchSize is not positive
+const defaultPurgeBatchSize = 1000
+
+// CacheJanitor deletes cache entries that expired more than a grace period
+// ago. Until then expired entries are kept, to be served while upstream is
+// down. Entries are deleted in bounded batches, so no statement holds locks
+// on a large part of a table.
+type CacheJanitor struct {
+	cache     *repository.CacheRepository
+	interval  time.Duration
+	grace     time.Duration
+	batchSize int
+}
+
+// NewCacheJanitor creates a new cache janitor
+func NewCacheJanitor(cache *repository.CacheRepository, cfg *config.Config) *CacheJanitor {
+	batchSize := cfg.Cache.PurgeBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultPurgeBatchSize
+	}
+	return &CacheJanitor{
+		cache:     cache,
+		interval:  cfg.Cache.PurgeInterval,
+		grace:     cfg.Cache.PurgeGrace,
+		batchSize: batchSize,
+	}
+}
+
+// Run deletes expired entries every purge interval, until ctx is cancelled
+func (j *CacheJanitor) Run(ctx context.Context) {
+	if j.interval <= 0 {
+		slog.Info("Expired cache purge disabled")
+		return
+	}
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := j.Purge(ctx, j.grace); err != nil && ctx.Err() == nil {
+			slog.Error("Failed to purge expired cache", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge deletes the entries of every cache type that expired more than
+// grace ago, and returns the number deleted per cache type. On error the
+// counts so far are returned with it.
+func (j *CacheJanitor) Purge(ctx context.Context, grace time.Duration) (map[string]int64, error) {
+	start := time.Now()
+	expiredBefore := start.Add(-grace)
+
+	deleted := make(map[string]int64)
+	var total int64
+	for _, cacheType := range repository.CacheTypes() {
+		for {
+			n, err := j.cache.DeleteExpired(ctx, cacheType, expiredBefore, j.batchSize)
+			deleted[cacheType] += n
+			total += n
+			cacheExpiredDeleted.Add(float64(n), cacheType)
+			if err != nil {
+				return deleted, err
+			}
+			if n < int64(j.batchSize) {
+				break
+			}
+		}
+	}
+
+	if total > 0 {
+		slog.Info("Expired cache purged", "deleted", deleted, "expiredBefore", expiredBefore,
+			"duration", time.Since(start))
+	} else {
+		slog.Debug("Expired cache purged", "deleted", 0, "expiredBefore", expiredBefore)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,101 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/msvens/mchess/internal/config"
+	"github.com/msvens/mchess/internal/model"
+	"github.com/msvens/mchess/internal/repository"
+	"github.com/msvens/mchess/internal/service"
+)
+
+func TestCacheJanitor_Purge(t *testing.T) {
+	database := newTestDB(t)
+	ctx := context.Background()
+
+	results := repository.NewResultsRepository(database.DB)
+	players := repository.NewPlayerRepository(database.DB)
+
+	now := time.Now()
+	at := func(d time.Duration) *time.Time {
+		expiresAt := now.Add(d)
+		return &expiresAt
+	}
+	saveResults := func(groupID int, expiresAt *time.Time) {
+		t.Helper()
+		if err := results.Save(ctx, groupID, repository.ResultTypeTable, json.RawMessage(`[]`), expiresAt); err != nil {
+			t.Fatalf("Save results %d: %v", groupID, err)
+		}
+	}
+	savePlayer := func(memberID int, expiresAt *time.Time) {
+		t.Helper()
+		ratingDate := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+		if err := players.Save(ctx, &model.PlayerInfo{ID: memberID}, ratingDate, expiresAt); err != nil {
+			t.Fatalf("Save player %d: %v", memberID, err)
+		}
+	}
+	count := func(table string) int {
+		t.Helper()
+		var n int
+		if err := database.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&n); err != nil {
+			t.Fatalf("Count %s: %v", table, err)
+		}
+		return n
+	}
+
+	// Results: 5 expired past the grace period, 2 within it, 2 that never
+	// expire and 1 that has not expired
+	for id := 1; id <= 5; id++ {
+		saveResults(id, at(-48*time.Hour))
+	}
+	saveResults(6, at(-time.Hour))
+	saveResults(7, at(-time.Hour))
+	saveResults(8, nil)
+	saveResults(9, nil)
+	saveResults(10, at(time.Hour))
+
+	// Players: 1 expired past the grace period, 1 that never expires
+	savePlayer(500001, at(-48*time.Hour))
+	savePlayer(500002, nil)
+
+	// Two entries per statement, so results take three batches
+	janitor := service.NewCacheJanitor(repository.NewCacheRepository(database.DB),
+		&config.Config{Cache: config.CacheConfig{PurgeBatchSize: 2}})
+
+	t.Run("DeletesExpiredPastGrace", func(t *testing.T) {
+		deleted, err := janitor.Purge(ctx, 24*time.Hour)
+		if err != nil {
+			t.Fatalf("Purge: %v", err)
+		}
+
+		want := map[string]int64{"player": 1, "results": 5, "ratinglist": 0, "organisation": 0, "tournament": 0}
+		if !maps.Equal(deleted, want) {
+			t.Errorf("Deleted: got %v, want %v", deleted, want)
+		}
+		if n := count("results_cache"); n != 5 {
+			t.Errorf("Results left: got %d, want 5", n)
+		}
+		if n := count("player_cache"); n != 1 {
+			t.Errorf("Players left: got %d, want 1", n)
+		}
+	})
+
+	t.Run("NoGrace_DeletesAllExpired", func(t *testing.T) {
+		deleted, err := janitor.Purge(ctx, 0)
+		if err != nil {
+			t.Fatalf("Purge: %v", err)
+		}
+		if deleted["results"] != 2 {
+			t.Errorf("Deleted results: got %d, want 2", deleted["results"])
+		}
+
+		// Only entries that never expire or have not expired are left
+		if n := count("results_cache"); n != 3 {
+			t.Errorf("Results left: got %d, want 3", n)
+		}
+	})
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/msvens/mchess/internal/config"
+	"github.com/msvens/mchess/internal/model"
+	"github.com/msvens/mchess/internal/repository"
+	"github.com/msvens/mchess/internal/upstream"
+)
+
+// OrganisationService handles federation, district and club lookups with caching.
+// Entries have a long TTL and are refreshed in the background before they expire.
+type OrganisationService struct {
+	repo            *repository.OrganisationRepository
+	upstream        *upstream.Client
+	stats           *StatsService
+	cacheTTL        time.Duration
+	refreshInterval time.Duration
+	batch           config.BatchConfig
+}
+
+// NewOrganisationService creates a new organisation service
+func NewOrganisationService(repo *repository.OrganisationRepository, client *upstream.Client, stats *StatsService, cfg *config.Config) *OrganisationService {
+	return &OrganisationService{
+		repo:            repo,
+		upstream:        client,
+		stats:           stats,
+		cacheTTL:        cfg.Cache.OrganisationTTL,
+		refreshInterval: cfg.Cache.OrganisationRefresh,
+		batch:           cfg.Batch,
+	}
+}
+
+// GetFederation retrieves the federation info
+func (s *OrganisationService) GetFederation(ctx context.Context) (*model.Federation, error) {
+	federation, err := decodeUpstream[model.Federation](s.GetFederationRaw(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return &federation, nil
+}
+
+// GetFederationRaw retrieves the federation info as the upstream JSON
+func (s *OrganisationService) GetFederationRaw(ctx context.Context) ([]byte, error) {
+	return s.get(ctx, repository.OrganisationKey{Kind: repository.OrganisationKindFederation})
+}
+
+// GetDistricts retrieves all districts
+func (s *OrganisationService) GetDistricts(ctx context.Context) ([]model.District, error) {
+	return decodeUpstream[[]model.District](s.GetDistrictsRaw(ctx))
+}
+
+// GetDistrictsRaw retrieves all districts as the upstream JSON
+func (s *OrganisationService) GetDistrictsRaw(ctx context.Context) ([]byte, error) {
+	return s.get(ctx, repository.OrganisationKey{Kind: repository.OrganisationKindDistricts})
+}
+
+// GetClubsInDistrict retrieves all clubs in a district
+func (s *OrganisationService) GetClubsInDistrict(ctx context.Context, districtID int) ([]model.Club, error) {
+	return decodeUpstream[[]model.Club](s.GetClubsInDistrictRaw(ctx, districtID))
+}
+
+// GetClubsInDistrictRaw retrieves all clubs in a district as the upstream JSON
+func (s *OrganisationService) GetClubsInDistrictRaw(ctx context.Context, districtID int) ([]byte, error) {
+	return s.get(ctx, repository.OrganisationKey{Kind: repository.OrganisationKindDistrictClubs, ID: districtID})
+}
+
+// GetClub retrieves a specific club
+func (s *OrganisationService) GetClub(ctx context.Context, clubID int) (*model.Club, error) {
+	club, err := decodeUpstream[model.Club](s.GetClubRaw(ctx, clubID))
+	if err != nil {
+		return nil, err
+	}
+	return &club, nil
+}
+
+// GetClubRaw retrieves a specific club as the upstream JSON
+func (s *OrganisationService) GetClubRaw(ctx context.Context, clubID int) ([]byte, error) {
+	return s.get(ctx, repository.OrganisationKey{Kind: repository.OrganisationKindClub, ID: clubID})
+}
+
+// MaxBatchSize returns the maximum number of IDs in a batch request (0 = unlimited)
+func (s *OrganisationService) MaxBatchSize() int {
+	return s.batch.MaxSize
+}
+
+// GetClubs retrieves multiple clubs in batch. Clubs that could not be
+// retrieved, including those not reached before the request deadline, are
+// reported as errors.
+func (s *OrganisationService) GetClubs(ctx context.Context, clubIDs []int) (*model.ClubsResponse, error) {
+	clubs, failed := getBatch(ctx, s.batch, CacheTypeOrganisation, clubIDs, func(ctx context.Context, clubID int) (model.Club, error) {
+		club, err := s.GetClub(ctx, clubID)
+		if err != nil {
+			return model.Club{}, err
+		}
+		return *club, nil
+	})
+
+	return &model.ClubsResponse{Clubs: clubs, Errors: failed}, nil
+}
+
+// RunRefresher re-fetches cache entries before they expire, until ctx is cancelled.
+// Entries expiring within two refresh intervals are refreshed on every tick,
+// so each entry gets at least one attempt before it expires. Entries that
+// expired more than an interval ago are left to be fetched on demand.
+func (s *OrganisationService) RunRefresher(ctx context.Context) {
+	if s.refreshInterval <= 0 {
+		slog.Info("Organisation cache refresher disabled")
+		return
+	}
+
+	ticker := time.NewTicker(s.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		s.refreshExpiring(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refreshExpiring refreshes the entries that are about to expire, using the
+// batch workers. Entries that no longer exist upstream are removed.
+func (s *OrganisationService) refreshExpiring(ctx context.Context) {
+	now := time.Now()
+	keys, err := s.repo.ListExpiring(ctx, now.Add(-s.refreshInterval), now.Add(2*s.refreshInterval))
+	if err != nil {
+		slog.Error("Failed to list expiring organisation cache", "error", err)
+		return
+	}
+	if len(keys) == 0 {
+		return
+	}
+
+	_, errs := runBatch(ctx, s.batch, keys, s.refresh)
+	if ctx.Err() != nil {
+		return
+	}
+
+	refreshed, removed := 0, 0
+	for i, err := range errs {
+		key := keys[i]
+		switch {
+		case err == nil:
+			refreshed++
+		case errors.Is(err, upstream.ErrNotFound):
+			if _, err := s.repo.Delete(ctx, key); err != nil {
+				slog.Error("Failed to remove organisation cache", "kind", key.Kind, "id", key.ID, "error", err)
+				continue
+			}
+			removed++
+		default:
+			slog.Warn("Failed to refresh organisation cache", "kind", key.Kind, "id", key.ID, "error", err)
+		}
+	}
+
+	slog.Info("Refreshed organisation cache", "refreshed", refreshed, "removed", removed, "expiring", len(keys))
+}
+
+// get implements the cache → upstream → store flow shared by all kinds,
+// falling back to an expired entry if upstream is unavailable. The upstream
+// JSON is cached and returned as is.
+func (s *OrganisationService) get(ctx context.Context, key repository.OrganisationKey) ([]byte, error) {
+	var cached json.RawMessage
+	if s.cached(ctx, key, &cached) {
+		return cached, nil
+	}
+
+	data, err := s.refresh(ctx, key)
+	if err != nil {
+		var stale json.RawMessage
+		if s.stale(ctx, key, &stale, err) {
+			return stale, nil
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+// cached looks up a cache entry, decoding it into result. Cache errors are
+// logged and treated as a miss.
+func (s *OrganisationService) cached(ctx context.Context, key repository.OrganisationKey, result interface{}) bool {
+	found, fetchedAt, err := s.repo.Get(ctx, key, result)
+	if err != nil {
+		slog.Error("Cache lookup failed", "error", err, "kind", key.Kind, "id", key.ID)
+		return false
+	}
+	if found {
+		slog.Debug("Cache hit", "kind", key.Kind, "id", key.ID)
+		s.stats.Hit(CacheTypeOrganisation, 1)
+		cacheHit(ctx, fetchedAt)
+		return true
+	}
+
+	slog.Debug("Cache miss, fetching from upstream", "kind", key.Kind, "id", key.ID)
+	s.stats.Miss(CacheTypeOrganisation, 1)
+	cacheMiss(ctx)
+	return false
+}
+
+// stale decodes an expired cache entry into result, to serve in place of
+// the upstream error err. Returns false if err does not mean upstream is
+// unavailable or there is no entry.
+func (s *OrganisationService) stale(ctx context.Context, key repository.OrganisationKey, result interface{}, err error) bool {
+	if !errors.Is(err, upstream.ErrUnavailable) {
+		return false
+	}
+
+	found, fetchedAt, staleErr := s.repo.GetStale(ctx, key, result)
+	if staleErr != nil {
+		slog.Error("Stale cache lookup failed", "error", staleErr, "kind", key.Kind, "id", key.ID)
+		return false
+	}
+	if !found {
+		return false
+	}
+
+	serveStale(ctx, CacheTypeOrganisation, fetchedAt, err, "kind", key.Kind, "id", key.ID)
+	return true
+}
+
+// refresh fetches an entry from upstream and stores it in the cache
+func (s *OrganisationService) refresh(ctx context.Context, key repository.OrganisationKey) ([]byte, error) {
+	s.stats.UpstreamCall(CacheTypeOrganisation, 1)
+	data, err := s.fetch(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("upstream fetch: %w", err)
+	}
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("decode response: %w", upstream.ErrInvalidResponse)
+	}
+
+	expiresAt := time.Now().Add(s.cacheTTL)
+	if err := s.repo.Save(ctx, key, json.RawMessage(data), expiresAt); err != nil {
+		slog.Error("Failed to cache organisation", "error", err, "kind", key.Kind, "id", key.ID)
+		// Continue even if caching fails
+	}
+
+	return data, nil
+}
+
+// fetch calls the upstream endpoint matching the key kind
+func (s *OrganisationService) fetch(ctx context.Context, key repository.OrganisationKey) ([]byte, error) {
+	switch key.Kind {
+	case repository.OrganisationKindFederation:
+		return s.upstream.GetFederationRaw(ctx)
+	case repository.OrganisationKindDistricts:
+		return s.upstream.GetDistrictsRaw(ctx)
+	case repository.OrganisationKindDistrictClubs:
+		return s.upstream.GetClubsInDistrictRaw(ctx, key.ID)
+	case repository.OrganisationKindClub:
+		return s.upstream.GetClubRaw(ctx, key.ID)
+	default:
+		return nil, fmt.Errorf("unknown organisation kind %q", key.Kind)
+	}
+}
@@ -2,9 +2,9 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
-	"sync"
 	"time"
 
 	"github.com/msvens/mchess/internal/config"
@@ -15,20 +15,29 @@ import (
 
 // PlayerService handles player-related business logic
 type PlayerService struct {
-	repo     *repository.PlayerRepository
-	upstream *upstream.Client
-	cacheTTL time.Duration
+	repo      *repository.PlayerRepository
+	upstream  *upstream.Client
+	stats     *StatsService
+	ttlPolicy TTLPolicy
+	batch     config.BatchConfig
 }
 
 // NewPlayerService creates a new player service
-func NewPlayerService(repo *repository.PlayerRepository, client *upstream.Client, cfg *config.Config) *PlayerService {
+func NewPlayerService(repo *repository.PlayerRepository, client *upstream.Client, stats *StatsService, cfg *config.Config) *PlayerService {
 	return &PlayerService{
-		repo:     repo,
-		upstream: client,
-		cacheTTL: cfg.Cache.TTL,
+		repo:      repo,
+		upstream:  client,
+		stats:     stats,
+		ttlPolicy: NewTTLPolicy(cfg.Cache.RatingTTL(CacheTypePlayer)),
+		batch:     cfg.Batch,
 	}
 }
 
+// MaxBatchSize returns the maximum number of IDs in a batch request (0 = unlimited)
+func (s *PlayerService) MaxBatchSize() int {
+	return s.batch.MaxSize
+}
+
 // GetPlayer retrieves a player, checking cache first then upstream
 func (s *PlayerService) GetPlayer(ctx context.Context, memberID int, date time.Time) (*model.PlayerInfo, error) {
 	ratingDate := normalizeToMonthStart(date)
@@ -41,14 +50,22 @@ func (s *PlayerService) GetPlayer(ctx context.Context, memberID int, date time.T
 	}
 	if cached != nil {
 		slog.Debug("Cache hit", "memberID", memberID, "date", ratingDate)
+		s.stats.Hit(CacheTypePlayer, 1)
+		playerHit(ctx, cached)
 		return cached, nil
 	}
 
 	slog.Debug("Cache miss, fetching from upstream", "memberID", memberID, "date", ratingDate)
+	s.stats.Miss(CacheTypePlayer, 1)
+	cacheMiss(ctx)
 
 	// Fetch from upstream
+	s.stats.UpstreamCall(CacheTypePlayer, 1)
 	player, err := s.upstream.GetPlayer(ctx, memberID, date.Format("2006-01-02"))
 	if err != nil {
+		if stale := s.stale(ctx, memberID, ratingDate, err); stale != nil {
+			return stale, nil
+		}
 		return nil, fmt.Errorf("upstream fetch: %w", err)
 	}
 
@@ -84,10 +101,18 @@ func (s *PlayerService) GetPlayers(ctx context.Context, memberIDs []int, date ti
 	}
 
 	slog.Debug("Batch lookup", "total", len(memberIDs), "cached", len(cached), "missing", len(missingIDs))
+	s.stats.Hit(CacheTypePlayer, len(memberIDs)-len(missingIDs))
+	s.stats.Miss(CacheTypePlayer, len(missingIDs))
+	for _, player := range cached {
+		playerHit(ctx, player)
+	}
+	if len(missingIDs) > 0 {
+		cacheMiss(ctx)
+	}
 
 	// Fetch missing from upstream in parallel
 	if len(missingIDs) > 0 {
-		fetched, errors := s.fetchPlayersParallel(ctx, missingIDs, date, ratingDate)
+		fetched, errors := s.fetchPlayersParallel(ctx, missingIDs, date)
 		for id, player := range fetched {
 			cached[id] = player
 		}
@@ -121,45 +146,167 @@ func (s *PlayerService) GetPlayers(ctx context.Context, memberIDs []int, date ti
 	return response, nil
 }
 
-// fetchPlayersParallel fetches multiple players from upstream concurrently
-func (s *PlayerService) fetchPlayersParallel(ctx context.Context, memberIDs []int, date time.Time, ratingDate time.Time) (map[int]*model.PlayerInfo, []model.PlayerError) {
-	results := make(map[int]*model.PlayerInfo)
-	var errors []model.PlayerError
-	var mu sync.Mutex
-	var wg sync.WaitGroup
+// PlayerLookup identifies a player in a batch lookup, by member ID or FIDE ID
+type PlayerLookup struct {
+	MemberID int
+	FideID   int
+	Date     time.Time
+}
 
-	dateStr := date.Format("2006-01-02")
-	expiresAt := s.determineTTL(ratingDate)
+// GetPlayersByLookup retrieves a batch of players, each with its own rating
+// date. Member ID lookups are served from the cache with one query per
+// rating date; the rest are fetched using a bounded worker pool.
+func (s *PlayerService) GetPlayersByLookup(ctx context.Context, lookups []PlayerLookup) (*model.PlayersResponse, error) {
+	players := make([]*model.PlayerInfo, len(lookups))
+
+	// Group member ID lookups by rating date
+	byDate := make(map[string][]int)
+	for i, l := range lookups {
+		if l.MemberID != 0 {
+			key := normalizeToMonthStart(l.Date).Format("2006-01-02")
+			byDate[key] = append(byDate[key], i)
+		}
+	}
 
-	for _, id := range memberIDs {
-		wg.Add(1)
-		go func(memberID int) {
-			defer wg.Done()
-
-			player, err := s.upstream.GetPlayer(ctx, memberID, dateStr)
-			mu.Lock()
-			defer mu.Unlock()
-
-			if err != nil {
-				slog.Warn("Failed to fetch player", "memberID", memberID, "error", err)
-				errors = append(errors, model.PlayerError{
-					ID:    memberID,
-					Error: err.Error(),
-				})
-				return
+	cachedCount := 0
+	for key, indexes := range byDate {
+		ratingDate, _ := time.Parse("2006-01-02", key)
+		memberIDs := make([]int, len(indexes))
+		for n, i := range indexes {
+			memberIDs[n] = lookups[i].MemberID
+		}
+
+		cached, err := s.repo.GetBatch(ctx, memberIDs, ratingDate)
+		if err != nil {
+			slog.Error("Batch cache lookup failed", "error", err, "date", ratingDate)
+			continue
+		}
+		for _, i := range indexes {
+			if player, found := cached[lookups[i].MemberID]; found {
+				players[i] = player
+				playerHit(ctx, player)
+				cachedCount++
 			}
+		}
+	}
 
-			// Cache the player
-			if saveErr := s.repo.Save(ctx, player, ratingDate, expiresAt); saveErr != nil {
-				slog.Error("Failed to cache player", "error", saveErr, "memberID", memberID)
+	// FIDE ID lookups do their own cache check and statistics
+	var missing []int
+	memberMissing := 0
+	for i, l := range lookups {
+		if players[i] == nil {
+			missing = append(missing, i)
+			if l.MemberID != 0 {
+				memberMissing++
 			}
+		}
+	}
+
+	slog.Debug("Batch lookup by date", "total", len(lookups), "dates", len(byDate), "cached", cachedCount, "missing", len(missing))
+	s.stats.Hit(CacheTypePlayer, cachedCount)
+	s.stats.Miss(CacheTypePlayer, memberMissing)
+	if memberMissing > 0 {
+		cacheMiss(ctx)
+	}
+
+	fetched, errs := runBatch(ctx, s.batch, missing, func(ctx context.Context, i int) (*model.PlayerInfo, error) {
+		l := lookups[i]
+		if l.MemberID != 0 {
+			return s.fetchPlayer(ctx, l.MemberID, l.Date)
+		}
+		return s.GetPlayerByFideID(ctx, l.FideID, l.Date)
+	})
+
+	response := &model.PlayersResponse{
+		Players: make([]model.PlayerInfo, 0, len(lookups)),
+	}
+	for n, i := range missing {
+		if errs[n] != nil {
+			l := lookups[i]
+			slog.Warn("Failed to fetch player", "memberID", l.MemberID, "fideID", l.FideID, "date", l.Date, "error", errs[n])
+			response.Errors = append(response.Errors, model.PlayerError{
+				ID:     l.MemberID,
+				FideID: l.FideID,
+				Date:   l.Date.Format("2006-01-02"),
+				Error:  errs[n].Error(),
+			})
+			continue
+		}
+		players[i] = fetched[n]
+	}
+
+	// Maintain order from input
+	for _, player := range players {
+		if player != nil {
+			response.Players = append(response.Players, *player)
+		}
+	}
+
+	return response, nil
+}
+
+// fetchPlayersParallel fetches multiple players from upstream using a bounded
+// worker pool. IDs that could not be fetched, including those not reached
+// before the request deadline, are reported as errors.
+func (s *PlayerService) fetchPlayersParallel(ctx context.Context, memberIDs []int, date time.Time) (map[int]*model.PlayerInfo, []model.PlayerError) {
+	players, errs := runBatch(ctx, s.batch, memberIDs, func(ctx context.Context, memberID int) (*model.PlayerInfo, error) {
+		return s.fetchPlayer(ctx, memberID, date)
+	})
+
+	results := make(map[int]*model.PlayerInfo)
+	var failed []model.PlayerError
+	for i, memberID := range memberIDs {
+		if errs[i] != nil {
+			slog.Warn("Failed to fetch player", "memberID", memberID, "error", errs[i])
+			failed = append(failed, model.PlayerError{
+				ID:    memberID,
+				Error: errs[i].Error(),
+			})
+			continue
+		}
+		results[memberID] = players[i]
+	}
 
-			results[memberID] = player
-		}(id)
+	return results, failed
+}
+
+// fetchPlayer fetches a player from upstream and caches it, falling back to
+// an expired cache entry if upstream is unavailable
+func (s *PlayerService) fetchPlayer(ctx context.Context, memberID int, date time.Time) (*model.PlayerInfo, error) {
+	ratingDate := normalizeToMonthStart(date)
+
+	s.stats.UpstreamCall(CacheTypePlayer, 1)
+	player, err := s.upstream.GetPlayer(ctx, memberID, date.Format("2006-01-02"))
+	if err != nil {
+		if stale := s.stale(ctx, memberID, ratingDate, err); stale != nil {
+			return stale, nil
+		}
+		return nil, err
 	}
 
-	wg.Wait()
-	return results, errors
+	// Cache the player
+	if saveErr := s.repo.Save(ctx, player, ratingDate, s.determineTTL(ratingDate)); saveErr != nil {
+		slog.Error("Failed to cache player", "error", saveErr, "memberID", memberID)
+	}
+	return player, nil
+}
+
+// RefetchPlayer fetches a player from upstream and replaces its cache entry,
+// whether or not the entry has expired. Unlike lookups it never falls back
+// to the cache.
+func (s *PlayerService) RefetchPlayer(ctx context.Context, memberID int, date time.Time) (*model.PlayerInfo, error) {
+	ratingDate := normalizeToMonthStart(date)
+
+	s.stats.UpstreamCall(CacheTypePlayer, 1)
+	player, err := s.upstream.GetPlayer(ctx, memberID, date.Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("upstream fetch: %w", err)
+	}
+
+	if err := s.repo.Save(ctx, player, ratingDate, s.determineTTL(ratingDate)); err != nil {
+		return nil, err
+	}
+	return player, nil
 }
 
 // GetPlayerRatings retrieves rating history for a player
@@ -184,11 +331,24 @@ func (s *PlayerService) GetPlayerRatings(ctx context.Context, memberID int, from
 	}
 
 	slog.Debug("Rating history lookup", "total", len(dates), "cached", len(cached), "missing", len(missingDates))
+	s.stats.Hit(CacheTypePlayer, len(dates)-len(missingDates))
+	s.stats.Miss(CacheTypePlayer, len(missingDates))
+	for _, player := range cached {
+		playerHit(ctx, player)
+	}
+	if len(missingDates) > 0 {
+		cacheMiss(ctx)
+	}
 
 	// Fetch missing from upstream
 	for _, d := range missingDates {
+		s.stats.UpstreamCall(CacheTypePlayer, 1)
 		player, err := s.upstream.GetPlayer(ctx, memberID, d.Format("2006-01-02"))
 		if err != nil {
+			if stale := s.stale(ctx, memberID, d, err); stale != nil {
+				cached[d.Format("2006-01-02")] = stale
+				continue
+			}
 			slog.Warn("Failed to fetch player for date", "memberID", memberID, "date", d, "error", err)
 			continue
 		}
@@ -236,14 +396,28 @@ func (s *PlayerService) GetPlayerByFideID(ctx context.Context, fideID int, date
 	}
 	if cached != nil {
 		slog.Debug("Cache hit by FIDE ID", "fideID", fideID, "date", ratingDate)
+		s.stats.Hit(CacheTypePlayer, 1)
+		playerHit(ctx, cached)
 		return cached, nil
 	}
 
 	slog.Debug("Cache miss by FIDE ID, fetching from upstream", "fideID", fideID, "date", ratingDate)
+	s.stats.Miss(CacheTypePlayer, 1)
+	cacheMiss(ctx)
 
 	// Fetch from upstream
+	s.stats.UpstreamCall(CacheTypePlayer, 1)
 	player, err := s.upstream.GetPlayerByFideID(ctx, fideID, date.Format("2006-01-02"))
 	if err != nil {
+		if errors.Is(err, upstream.ErrUnavailable) {
+			stale, fetchedAt, staleErr := s.repo.GetStaleByFideID(ctx, fideID, ratingDate)
+			if staleErr != nil {
+				slog.Error("Stale cache lookup by FIDE ID failed", "error", staleErr, "fideID", fideID)
+			} else if stale != nil {
+				serveStale(ctx, CacheTypePlayer, fetchedAt, err, "fideID", fideID, "date", ratingDate)
+				return stale, nil
+			}
+		}
 		return nil, fmt.Errorf("upstream fetch by FIDE ID: %w", err)
 	}
 
@@ -259,19 +433,39 @@ func (s *PlayerService) GetPlayerByFideID(ctx context.Context, fideID int, date
 	return player, nil
 }
 
-// determineTTL calculates the cache expiration time based on the rating date
-func (s *PlayerService) determineTTL(ratingDate time.Time) *time.Time {
-	now := time.Now()
-	currentMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+// stale returns an expired cache entry to serve in place of the upstream
+// error err, or nil if err does not mean upstream is unavailable or there
+// is no entry
+func (s *PlayerService) stale(ctx context.Context, memberID int, ratingDate time.Time, err error) *model.PlayerInfo {
+	if !errors.Is(err, upstream.ErrUnavailable) {
+		return nil
+	}
 
-	// Historical data: never expires
-	if ratingDate.Before(currentMonth) {
+	player, fetchedAt, staleErr := s.repo.GetStale(ctx, memberID, ratingDate)
+	if staleErr != nil {
+		slog.Error("Stale cache lookup failed", "error", staleErr, "memberID", memberID)
+		return nil
+	}
+	if player == nil {
 		return nil
 	}
 
-	// Current month: use configured TTL
-	expires := now.Add(s.cacheTTL)
-	return &expires
+	serveStale(ctx, CacheTypePlayer, fetchedAt, err, "memberID", memberID, "date", ratingDate)
+	return player
+}
+
+// playerHit records a player served from the cache
+func playerHit(ctx context.Context, player *model.PlayerInfo) {
+	var fetchedAt time.Time
+	if player.CachedAt != nil {
+		fetchedAt = *player.CachedAt
+	}
+	cacheHit(ctx, fetchedAt)
+}
+
+// determineTTL calculates the cache expiration time based on the rating date
+func (s *PlayerService) determineTTL(ratingDate time.Time) *time.Time {
+	return s.ttlPolicy.Expiry(ratingDate, time.Now())
 }
 
 // normalizeToMonthStart returns the first day of the month for the given date
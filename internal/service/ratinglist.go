@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/msvens/mchess/internal/config"
+	"github.com/msvens/mchess/internal/model"
+	"github.com/msvens/mchess/internal/repository"
+	"github.com/msvens/mchess/internal/upstream"
+)
+
+// RatingListService handles rating list lookups backed by monthly snapshots
+type RatingListService struct {
+	repo      *repository.RatingListRepository
+	upstream  *upstream.Client
+	stats     *StatsService
+	ttlPolicy TTLPolicy
+}
+
+// NewRatingListService creates a new rating list service
+func NewRatingListService(repo *repository.RatingListRepository, client *upstream.Client, stats *StatsService, cfg *config.Config) *RatingListService {
+	return &RatingListService{
+		repo:      repo,
+		upstream:  client,
+		stats:     stats,
+		ttlPolicy: NewTTLPolicy(cfg.Cache.RatingTTL(CacheTypeRatingList)),
+	}
+}
+
+// GetFederationRatingList retrieves the federation-wide rating list
+func (s *RatingListService) GetFederationRatingList(ctx context.Context, date time.Time, ratingType, category int) ([]model.PlayerInfo, error) {
+	return decodeUpstream[[]model.PlayerInfo](s.GetFederationRatingListRaw(ctx, date, ratingType, category))
+}
+
+// GetFederationRatingListRaw retrieves the federation-wide rating list as the upstream JSON
+func (s *RatingListService) GetFederationRatingListRaw(ctx context.Context, date time.Time, ratingType, category int) ([]byte, error) {
+	key := repository.RatingListKey{
+		Scope:      repository.RatingListScopeFederation,
+		RatingDate: normalizeToMonthStart(date),
+		RatingType: ratingType,
+		Category:   category,
+	}
+	return s.get(ctx, key, date, func(dateStr string) ([]byte, error) {
+		return s.upstream.GetFederationRatingListRaw(ctx, dateStr, ratingType, category)
+	})
+}
+
+// GetDistrictRatingList retrieves the rating list for a district
+func (s *RatingListService) GetDistrictRatingList(ctx context.Context, districtID int, date time.Time, ratingType, category int) ([]model.PlayerInfo, error) {
+	return decodeUpstream[[]model.PlayerInfo](s.GetDistrictRatingListRaw(ctx, districtID, date, ratingType, category))
+}
+
+// GetDistrictRatingListRaw retrieves the rating list for a district as the upstream JSON
+func (s *RatingListService) GetDistrictRatingListRaw(ctx context.Context, districtID int, date time.Time, ratingType, category int) ([]byte, error) {
+	key := repository.RatingListKey{
+		Scope:      repository.RatingListScopeDistrict,
+		ScopeID:    districtID,
+		RatingDate: normalizeToMonthStart(date),
+		RatingType: ratingType,
+		Category:   category,
+	}
+	return s.get(ctx, key, date, func(dateStr string) ([]byte, error) {
+		return s.upstream.GetDistrictRatingListRaw(ctx, districtID, dateStr, ratingType, category)
+	})
+}
+
+// GetClubRatingList retrieves the rating list for a club
+func (s *RatingListService) GetClubRatingList(ctx context.Context, clubID int, date time.Time, ratingType, category int) ([]model.PlayerInfo, error) {
+	return decodeUpstream[[]model.PlayerInfo](s.GetClubRatingListRaw(ctx, clubID, date, ratingType, category))
+}
+
+// GetClubRatingListRaw retrieves the rating list for a club as the upstream JSON
+func (s *RatingListService) GetClubRatingListRaw(ctx context.Context, clubID int, date time.Time, ratingType, category int) ([]byte, error) {
+	key := repository.RatingListKey{
+		Scope:      repository.RatingListScopeClub,
+		ScopeID:    clubID,
+		RatingDate: normalizeToMonthStart(date),
+		RatingType: ratingType,
+		Category:   category,
+	}
+	return s.get(ctx, key, date, func(dateStr string) ([]byte, error) {
+		return s.upstream.GetClubRatingListRaw(ctx, clubID, dateStr, ratingType, category)
+	})
+}
+
+// get implements the snapshot → upstream → store flow shared by all scopes.
+// The upstream JSON is cached and returned as is.
+func (s *RatingListService) get(ctx context.Context, key repository.RatingListKey, date time.Time,
+	fetch func(dateStr string) ([]byte, error)) ([]byte, error) {
+
+	// Check snapshot first
+	cached, fetchedAt, err := s.repo.Get(ctx, key)
+	if err != nil {
+		slog.Error("Cache lookup failed", "error", err, "scope", key.Scope, "id", key.ScopeID)
+		// Continue to upstream on cache error
+	}
+	if cached != nil {
+		slog.Debug("Cache hit", "scope", key.Scope, "id", key.ScopeID, "date", key.RatingDate)
+		s.stats.Hit(CacheTypeRatingList, 1)
+		cacheHit(ctx, fetchedAt)
+		return cached, nil
+	}
+
+	slog.Debug("Cache miss, fetching from upstream", "scope", key.Scope, "id", key.ScopeID, "date", key.RatingDate)
+	s.stats.Miss(CacheTypeRatingList, 1)
+	cacheMiss(ctx)
+	s.stats.UpstreamCall(CacheTypeRatingList, 1)
+
+	data, err := fetch(date.Format("2006-01-02"))
+	if err != nil {
+		if stale := s.stale(ctx, key, err); stale != nil {
+			return stale, nil
+		}
+		return nil, fmt.Errorf("upstream fetch: %w", err)
+	}
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("decode response: %w", upstream.ErrInvalidResponse)
+	}
+
+	// Same policy as players: past months are immutable
+	expiresAt := s.ttlPolicy.Expiry(key.RatingDate, time.Now())
+
+	if err := s.repo.Save(ctx, key, data, expiresAt); err != nil {
+		slog.Error("Failed to cache rating list", "error", err, "scope", key.Scope, "id", key.ScopeID)
+		// Continue even if caching fails
+	}
+
+	return data, nil
+}
+
+// stale returns an expired snapshot to serve in place of the upstream error
+// err, or nil if err does not mean upstream is unavailable or there is no
+// snapshot
+func (s *RatingListService) stale(ctx context.Context, key repository.RatingListKey, err error) []byte {
+	if !errors.Is(err, upstream.ErrUnavailable) {
+		return nil
+	}
+
+	data, fetchedAt, staleErr := s.repo.GetStale(ctx, key)
+	if staleErr != nil {
+		slog.Error("Stale cache lookup failed", "error", staleErr, "scope", key.Scope, "id", key.ScopeID)
+		return nil
+	}
+	if data == nil {
+		return nil
+	}
+
+	serveStale(ctx, CacheTypeRatingList, fetchedAt, err, "scope", key.Scope, "id", key.ScopeID, "date", key.RatingDate)
+	return data
+}
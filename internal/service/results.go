@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/msvens/mchess/internal/config"
+	"github.com/msvens/mchess/internal/model"
+	"github.com/msvens/mchess/internal/repository"
+	"github.com/msvens/mchess/internal/upstream"
+)
+
+// groupTournaments looks up the tournament of a group, implemented by
+// TournamentService
+type groupTournaments interface {
+	GetTournamentFromGroup(ctx context.Context, groupID int) (*model.Tournament, error)
+}
+
+// ResultsService handles tournament results lookups with caching
+type ResultsService struct {
+	repo        *repository.ResultsRepository
+	upstream    *upstream.Client
+	tournaments groupTournaments
+	stats       *StatsService
+	cacheTTL    time.Duration
+	batch       config.BatchConfig
+}
+
+// NewResultsService creates a new results service
+func NewResultsService(repo *repository.ResultsRepository, client *upstream.Client, tournaments *TournamentService, stats *StatsService, cfg *config.Config) *ResultsService {
+	return &ResultsService{
+		repo:        repo,
+		upstream:    client,
+		tournaments: tournaments,
+		stats:       stats,
+		cacheTTL:    cfg.Cache.ResultsTTL,
+		batch:       cfg.Batch,
+	}
+}
+
+// GetResultTable retrieves the individual standings for a group
+func (s *ResultsService) GetResultTable(ctx context.Context, groupID int) ([]model.TournamentEndResult, error) {
+	return decodeUpstream[[]model.TournamentEndResult](s.GetResultTableRaw(ctx, groupID))
+}
+
+// GetResultTableRaw retrieves the individual standings for a group as the
+// upstream JSON
+func (s *ResultsService) GetResultTableRaw(ctx context.Context, groupID int) ([]byte, error) {
+	return s.get(ctx, groupID, repository.ResultTypeTable, s.upstream.GetResultTableRaw,
+		func([]byte) ([]model.TournamentRoundResult, error) {
+			return s.GetRoundResults(ctx, groupID)
+		})
+}
+
+// MaxBatchSize returns the maximum number of IDs in a batch request (0 = unlimited)
+func (s *ResultsService) MaxBatchSize() int {
+	return s.batch.MaxSize
+}
+
+// GetResultTables retrieves the individual standings for multiple groups in
+// batch. Groups that could not be retrieved, including those not reached
+// before the request deadline, are reported as errors.
+func (s *ResultsService) GetResultTables(ctx context.Context, groupIDs []int) (*model.ResultTablesResponse, error) {
+	tables, failed := getBatch(ctx, s.batch, CacheTypeResults, groupIDs, func(ctx context.Context, groupID int) (model.ResultTable, error) {
+		results, err := s.GetResultTable(ctx, groupID)
+		if err != nil {
+			return model.ResultTable{}, err
+		}
+		return model.ResultTable{GroupID: groupID, Results: results}, nil
+	})
+
+	return &model.ResultTablesResponse{Tables: tables, Errors: failed}, nil
+}
+
+// GetRoundResults retrieves the individual round results for a group
+func (s *ResultsService) GetRoundResults(ctx context.Context, groupID int) ([]model.TournamentRoundResult, error) {
+	return decodeRounds(s.GetRoundResultsRaw(ctx, groupID))
+}
+
+// GetRoundResultsRaw retrieves the individual round results for a group as
+// the upstream JSON
+func (s *ResultsService) GetRoundResultsRaw(ctx context.Context, groupID int) ([]byte, error) {
+	return s.get(ctx, groupID, repository.ResultTypeRoundResults, s.upstream.GetRoundResultsRaw,
+		func(data []byte) ([]model.TournamentRoundResult, error) {
+			return decodeRounds(data, nil)
+		})
+}
+
+// GetTeamResultTable retrieves the team standings for a group
+func (s *ResultsService) GetTeamResultTable(ctx context.Context, groupID int) ([]model.TeamTournamentEndResult, error) {
+	return decodeUpstream[[]model.TeamTournamentEndResult](s.GetTeamResultTableRaw(ctx, groupID))
+}
+
+// GetTeamResultTableRaw retrieves the team standings for a group as the
+// upstream JSON
+func (s *ResultsService) GetTeamResultTableRaw(ctx context.Context, groupID int) ([]byte, error) {
+	return s.get(ctx, groupID, repository.ResultTypeTeamTable, s.upstream.GetTeamResultTableRaw,
+		func([]byte) ([]model.TournamentRoundResult, error) {
+			return s.GetTeamRoundResults(ctx, groupID)
+		})
+}
+
+// GetTeamRoundResults retrieves the team round results for a group
+func (s *ResultsService) GetTeamRoundResults(ctx context.Context, groupID int) ([]model.TournamentRoundResult, error) {
+	return decodeRounds(s.GetTeamRoundResultsRaw(ctx, groupID))
+}
+
+// GetTeamRoundResultsRaw retrieves the team round results for a group as
+// the upstream JSON
+func (s *ResultsService) GetTeamRoundResultsRaw(ctx context.Context, groupID int) ([]byte, error) {
+	return s.get(ctx, groupID, repository.ResultTypeTeamRoundResults, s.upstream.GetTeamRoundResultsRaw,
+		func(data []byte) ([]model.TournamentRoundResult, error) {
+			return decodeRounds(data, nil)
+		})
+}
+
+// get implements the cache → upstream → store flow shared by all result
+// types. The upstream JSON is cached and returned as is. rounds returns the
+// round results used to decide whether the group is finalized, given the
+// fetched JSON.
+func (s *ResultsService) get(ctx context.Context, groupID int, resultType string,
+	fetch func(context.Context, int) ([]byte, error), rounds func([]byte) ([]model.TournamentRoundResult, error)) ([]byte, error) {
+
+	// Check cache first
+	var cached json.RawMessage
+	found, fetchedAt, err := s.repo.Get(ctx, groupID, resultType, &cached)
+	if err != nil {
+		slog.Error("Cache lookup failed", "error", err, "groupID", groupID, "type", resultType)
+		// Continue to upstream on cache error
+	}
+	if found {
+		slog.Debug("Cache hit", "groupID", groupID, "type", resultType)
+		s.stats.Hit(CacheTypeResults, 1)
+		cacheHit(ctx, fetchedAt)
+		return cached, nil
+	}
+
+	slog.Debug("Cache miss, fetching from upstream", "groupID", groupID, "type", resultType)
+	s.stats.Miss(CacheTypeResults, 1)
+	cacheMiss(ctx)
+	s.stats.UpstreamCall(CacheTypeResults, 1)
+
+	data, err := fetch(ctx, groupID)
+	if err != nil {
+		if stale := s.stale(ctx, groupID, resultType, err); stale != nil {
+			return stale, nil
+		}
+		return nil, fmt.Errorf("upstream fetch: %w", err)
+	}
+	if !json.Valid(data) {
+		return nil, fmt.Errorf("decode response: %w", upstream.ErrInvalidResponse)
+	}
+
+	expiresAt := s.determineTTL(ctx, groupID, func() ([]model.TournamentRoundResult, error) {
+		return rounds(data)
+	})
+
+	if err := s.repo.Save(ctx, groupID, resultType, json.RawMessage(data), expiresAt); err != nil {
+		slog.Error("Failed to cache results", "error", err, "groupID", groupID, "type", resultType)
+		// Continue even if caching fails
+	}
+
+	return data, nil
+}
+
+// stale returns an expired cache entry to serve in place of the upstream
+// error err, or nil if err does not mean upstream is unavailable or there
+// is no entry
+func (s *ResultsService) stale(ctx context.Context, groupID int, resultType string, err error) []byte {
+	if !errors.Is(err, upstream.ErrUnavailable) {
+		return nil
+	}
+
+	var data json.RawMessage
+	found, fetchedAt, staleErr := s.repo.GetStale(ctx, groupID, resultType, &data)
+	if staleErr != nil {
+		slog.Error("Stale cache lookup failed", "error", staleErr, "groupID", groupID, "type", resultType)
+		return nil
+	}
+	if !found {
+		return nil
+	}
+
+	serveStale(ctx, CacheTypeResults, fetchedAt, err, "groupID", groupID, "type", resultType)
+	return data
+}
+
+// decodeRounds decodes round results JSON returned with err by a raw lookup
+func decodeRounds(data []byte, err error) ([]model.TournamentRoundResult, error) {
+	return decodeUpstream[[]model.TournamentRoundResult](data, err)
+}
+
+// determineTTL calculates the cache expiration time for a group's results.
+// Results never expire once the group has ended and every round is finalized.
+// The group end comes from the cached tournament, so round results are only
+// fetched for groups that have ended.
+func (s *ResultsService) determineTTL(ctx context.Context, groupID int, rounds func() ([]model.TournamentRoundResult, error)) *time.Time {
+	now := time.Now()
+	expires := now.Add(s.cacheTTL)
+
+	end, err := s.groupEnd(ctx, groupID)
+	if err != nil {
+		slog.Warn("Failed to fetch group for TTL", "groupID", groupID, "error", err)
+		return &expires
+	}
+	if end == nil || !end.Before(now) {
+		return &expires
+	}
+
+	roundResults, err := rounds()
+	if err != nil {
+		slog.Warn("Failed to fetch round results for TTL", "groupID", groupID, "error", err)
+		return &expires
+	}
+	if !roundsFinalized(roundResults) {
+		return &expires
+	}
+
+	// Finalized results: never expire
+	return nil
+}
+
+// groupEnd returns the end date of a group, falling back to the tournament
+// end date when the group has none
+func (s *ResultsService) groupEnd(ctx context.Context, groupID int) (*time.Time, error) {
+	tournament, err := s.tournaments.GetTournamentFromGroup(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, class := range tournament.RootClasses {
+		for _, group := range class.Groups {
+			if group.ID == groupID && group.End != nil && !group.End.IsZero() {
+				return &group.End.Time, nil
+			}
+		}
+	}
+
+	if tournament.End != nil && !tournament.End.IsZero() {
+		return &tournament.End.Time, nil
+	}
+	return nil, nil
+}
+
+// roundsFinalized reports whether every round result has been finalized
+func roundsFinalized(rounds []model.TournamentRoundResult) bool {
+	if len(rounds) == 0 {
+		return false
+	}
+	for _, round := range rounds {
+		if !round.Finalized {
+			return false
+		}
+	}
+	return true
+}
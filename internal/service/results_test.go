@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/msvens/mchess/internal/model"
+)
+
+// stubGroupTournaments returns the same tournament for every group
+type stubGroupTournaments struct {
+	tournament *model.Tournament
+	err        error
+}
+
+func (s stubGroupTournaments) GetTournamentFromGroup(ctx context.Context, groupID int) (*model.Tournament, error) {
+	return s.tournament, s.err
+}
+
+func TestResultsService_DetermineTTL(t *testing.T) {
+	const groupID = 17001
+	ttl := time.Hour
+	past := &model.Date{Time: time.Now().AddDate(0, 0, -7)}
+	future := &model.Date{Time: time.Now().AddDate(0, 0, 7)}
+
+	// tournament returns a tournament whose group ends at groupEnd and
+	// which itself ends at end
+	tournament := func(groupEnd, end *model.Date) *model.Tournament {
+		return &model.Tournament{
+			ID:  6058,
+			End: end,
+			RootClasses: []model.TournamentClass{{
+				Groups: []model.TournamentClassGroup{{ID: groupID, End: groupEnd}},
+			}},
+		}
+	}
+	finalized := []model.TournamentRoundResult{{RoundNr: 1, Finalized: true}, {RoundNr: 2, Finalized: true}}
+	live := []model.TournamentRoundResult{{RoundNr: 1, Finalized: true}, {RoundNr: 2}}
+
+	tests := []struct {
+		name        string
+		rounds      []model.TournamentRoundResult
+		roundsErr   error
+		tournaments stubGroupTournaments
+		wantRounds  bool // Round results fetched
+		wantNever   bool
+	}{
+		{
+			name:        "Live",
+			rounds:      live,
+			tournaments: stubGroupTournaments{tournament: tournament(past, past)},
+			wantRounds:  true,
+		},
+		{
+			name:        "NoRounds",
+			tournaments: stubGroupTournaments{tournament: tournament(past, past)},
+			wantRounds:  true,
+		},
+		{
+			name:        "RoundsUnavailable",
+			roundsErr:   errors.New("upstream unavailable"),
+			tournaments: stubGroupTournaments{tournament: tournament(past, past)},
+			wantRounds:  true,
+		},
+		{
+			name:        "FinalizedNotEnded",
+			rounds:      finalized,
+			tournaments: stubGroupTournaments{tournament: tournament(future, past)},
+		},
+		{
+			name:        "FinalizedNoEndDate",
+			rounds:      finalized,
+			tournaments: stubGroupTournaments{tournament: tournament(nil, nil)},
+		},
+		{
+			name:        "FinalizedGroupUnavailable",
+			rounds:      finalized,
+			tournaments: stubGroupTournaments{err: errors.New("upstream unavailable")},
+		},
+		{
+			name:        "FinalizedAndEnded",
+			rounds:      finalized,
+			tournaments: stubGroupTournaments{tournament: tournament(past, future)},
+			wantRounds:  true,
+			wantNever:   true,
+		},
+		{
+			name:        "FinalizedAndTournamentEnded",
+			rounds:      finalized,
+			tournaments: stubGroupTournaments{tournament: tournament(nil, past)},
+			wantRounds:  true,
+			wantNever:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ResultsService{tournaments: tt.tournaments, cacheTTL: ttl}
+
+			before := time.Now()
+			fetched := false
+			expiresAt := s.determineTTL(context.Background(), groupID, func() ([]model.TournamentRoundResult, error) {
+				fetched = true
+				return tt.rounds, tt.roundsErr
+			})
+
+			if fetched != tt.wantRounds {
+				t.Errorf("Round results fetched: got %v, want %v", fetched, tt.wantRounds)
+			}
+
+			if tt.wantNever {
+				if expiresAt != nil {
+					t.Errorf("Expires at %v, want never", *expiresAt)
+				}
+				return
+			}
+			if expiresAt == nil {
+				t.Fatal("Never expires, want after the results TTL")
+			}
+			if expiresAt.Before(before.Add(ttl)) || expiresAt.After(time.Now().Add(ttl)) {
+				t.Errorf("Expires at %v, want %v from now", *expiresAt, ttl)
+			}
+		})
+	}
+}
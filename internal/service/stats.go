@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/msvens/mchess/internal/config"
+	"github.com/msvens/mchess/internal/metrics"
+	"github.com/msvens/mchess/internal/model"
+	"github.com/msvens/mchess/internal/repository"
+)
+
+// Cache types used for statistics (rows in cache_stats)
+const (
+	CacheTypePlayer       = "player"
+	CacheTypeTournament   = "tournament"
+	CacheTypeResults      = "results"
+	CacheTypeRatingList   = "ratinglist"
+	CacheTypeOrganisation = "organisation"
+)
+
+var (
+	cacheHits = metrics.NewCounterVec(
+		"mchess_cache_hits_total",
+		"Total number of cache hits by cache type.",
+		"cache",
+	)
+	cacheMisses = metrics.NewCounterVec(
+		"mchess_cache_misses_total",
+		"Total number of cache misses by cache type.",
+		"cache",
+	)
+	cacheUpstreamCalls = metrics.NewCounterVec(
+		"mchess_cache_upstream_calls_total",
+		"Total number of upstream calls made on behalf of a cache, by cache type.",
+		"cache",
+	)
+)
+
+// statsCounts holds counts that have not been written to the database yet
+type statsCounts struct {
+	hits          int64
+	misses        int64
+	upstreamCalls int64
+}
+
+// statsStore persists cache statistics, implemented by
+// repository.StatsRepository
+type statsStore interface {
+	Increment(ctx context.Context, cacheType string, hits, misses, upstreamCalls int64) error
+	List(ctx context.Context) ([]model.CacheStats, error)
+}
+
+// StatsService records cache hits, misses and upstream calls.
+// Counts are buffered in memory and flushed to cache_stats periodically.
+// A nil *StatsService is valid and records nothing.
+type StatsService struct {
+	repo          statsStore
+	flushInterval time.Duration
+
+	mu      sync.Mutex
+	pending map[string]*statsCounts
+}
+
+// NewStatsService creates a new stats service
+func NewStatsService(repo *repository.StatsRepository, cfg *config.Config) *StatsService {
+	return &StatsService{
+		repo:          repo,
+		flushInterval: cfg.Cache.StatsFlushInterval,
+		pending:       make(map[string]*statsCounts),
+	}
+}
+
+// Hit records n cache hits
+func (s *StatsService) Hit(cacheType string, n int) {
+	s.add(cacheType, statsCounts{hits: int64(n)})
+}
+
+// Miss records n cache misses
+func (s *StatsService) Miss(cacheType string, n int) {
+	s.add(cacheType, statsCounts{misses: int64(n)})
+}
+
+// UpstreamCall records n upstream calls made on behalf of a cache
+func (s *StatsService) UpstreamCall(cacheType string, n int) {
+	s.add(cacheType, statsCounts{upstreamCalls: int64(n)})
+}
+
+func (s *StatsService) add(cacheType string, c statsCounts) {
+	// Metrics are process-wide and recorded even without a stats service
+	cacheHits.Add(float64(c.hits), cacheType)
+	cacheMisses.Add(float64(c.misses), cacheType)
+	cacheUpstreamCalls.Add(float64(c.upstreamCalls), cacheType)
+
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.pending[cacheType]
+	if !ok {
+		p = &statsCounts{}
+		s.pending[cacheType] = p
+	}
+	p.hits += c.hits
+	p.misses += c.misses
+	p.upstreamCalls += c.upstreamCalls
+}
+
+// RunFlusher flushes buffered counts every flush interval until ctx is cancelled
+func (s *StatsService) RunFlusher(ctx context.Context) {
+	if s.flushInterval <= 0 {
+		slog.Warn("Cache stats flush interval not set, stats will only be flushed on shutdown")
+		return
+	}
+
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Flush(ctx); err != nil {
+				slog.Error("Failed to flush cache stats", "error", err)
+			}
+		}
+	}
+}
+
+// Flush writes all buffered counts to the database. Counts that fail to
+// write are kept in the buffer for the next flush.
+func (s *StatsService) Flush(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[string]*statsCounts)
+	s.mu.Unlock()
+
+	var firstErr error
+	for cacheType, c := range pending {
+		if err := s.repo.Increment(ctx, cacheType, c.hits, c.misses, c.upstreamCalls); err != nil {
+			s.add(cacheType, *c)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("flush %s stats: %w", cacheType, err)
+			}
+		}
+	}
+
+	return firstErr
+}
+
+// GetStats returns statistics for all cache types, including counts that
+// have not been flushed yet
+func (s *StatsService) GetStats(ctx context.Context) (*model.CacheStatsResponse, error) {
+	stored, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	pending := make(map[string]statsCounts, len(s.pending))
+	for cacheType, c := range s.pending {
+		pending[cacheType] = *c
+	}
+	s.mu.Unlock()
+
+	for i := range stored {
+		c := pending[stored[i].CacheType]
+		stored[i].Hits += c.hits
+		stored[i].Misses += c.misses
+		stored[i].UpstreamCalls += c.upstreamCalls
+		delete(pending, stored[i].CacheType)
+	}
+	// Cache types that have no row yet
+	for cacheType, c := range pending {
+		stored = append(stored, model.CacheStats{
+			CacheType:     cacheType,
+			Hits:          c.hits,
+			Misses:        c.misses,
+			UpstreamCalls: c.upstreamCalls,
+			LastReset:     time.Now(),
+		})
+	}
+
+	sort.Slice(stored, func(i, j int) bool { return stored[i].CacheType < stored[j].CacheType })
+	for i := range stored {
+		if total := stored[i].Hits + stored[i].Misses; total > 0 {
+			stored[i].HitRatio = float64(stored[i].Hits) / float64(total)
+		}
+	}
+
+	return &model.CacheStatsResponse{Stats: stored}, nil
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/msvens/mchess/internal/model"
+)
+
+// stubStatsStore keeps cache stats in memory and fails the next failures
+// Increment calls
+type stubStatsStore struct {
+	mu       sync.Mutex
+	stats    map[string]model.CacheStats
+	calls    int
+	failures int
+}
+
+func newStubStatsStore() *stubStatsStore {
+	return &stubStatsStore{stats: make(map[string]model.CacheStats)}
+}
+
+func (s *stubStatsStore) Increment(ctx context.Context, cacheType string, hits, misses, upstreamCalls int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls++
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("database unavailable")
+	}
+	stats := s.stats[cacheType]
+	stats.CacheType = cacheType
+	stats.Hits += hits
+	stats.Misses += misses
+	stats.UpstreamCalls += upstreamCalls
+	s.stats[cacheType] = stats
+	return nil
+}
+
+func (s *stubStatsStore) List(ctx context.Context) ([]model.CacheStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var result []model.CacheStats
+	for _, stats := range s.stats {
+		stats.LastReset = time.Now()
+		result = append(result, stats)
+	}
+	return result, nil
+}
+
+func newTestStatsService(store statsStore) *StatsService {
+	return &StatsService{repo: store, pending: make(map[string]*statsCounts)}
+}
+
+func TestStatsService_Flush(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("BuffersUntilFlush", func(t *testing.T) {
+		store := newStubStatsStore()
+		s := newTestStatsService(store)
+
+		s.Hit(CacheTypePlayer, 3)
+		s.Hit(CacheTypePlayer, 2)
+		s.Miss(CacheTypePlayer, 1)
+		s.UpstreamCall(CacheTypeTournament, 4)
+		if store.calls != 0 {
+			t.Fatalf("Increment calls before flush: got %d, want 0", store.calls)
+		}
+
+		if err := s.Flush(ctx); err != nil {
+			t.Fatalf("Flush: %v", err)
+		}
+		if store.calls != 2 {
+			t.Errorf("Increment calls: got %d, want one per cache type (2)", store.calls)
+		}
+		if got := store.stats[CacheTypePlayer]; got.Hits != 5 || got.Misses != 1 {
+			t.Errorf("Player stats: got %d hits, %d misses, want 5, 1", got.Hits, got.Misses)
+		}
+		if got := store.stats[CacheTypeTournament]; got.UpstreamCalls != 4 {
+			t.Errorf("Tournament upstream calls: got %d, want 4", got.UpstreamCalls)
+		}
+
+		// Nothing left to write
+		if err := s.Flush(ctx); err != nil || store.calls != 2 {
+			t.Errorf("Second flush: got %v after %d calls, want nothing written", err, store.calls)
+		}
+	})
+
+	t.Run("FailedFlush_Requeued", func(t *testing.T) {
+		store := newStubStatsStore()
+		store.failures = 1
+		s := newTestStatsService(store)
+
+		s.Hit(CacheTypePlayer, 3)
+		if err := s.Flush(ctx); err == nil {
+			t.Fatal("Flush: got success, want error")
+		}
+
+		// Counts recorded after the failed flush are added to the requeued ones
+		s.Hit(CacheTypePlayer, 1)
+		if err := s.Flush(ctx); err != nil {
+			t.Fatalf("Second flush: %v", err)
+		}
+		if got := store.stats[CacheTypePlayer].Hits; got != 4 {
+			t.Errorf("Hits: got %d, want 4", got)
+		}
+	})
+
+	t.Run("NilService", func(t *testing.T) {
+		var s *StatsService
+		s.Hit(CacheTypePlayer, 1)
+		if err := s.Flush(ctx); err != nil {
+			t.Errorf("Flush: %v", err)
+		}
+	})
+}
+
+func TestStatsService_GetStats(t *testing.T) {
+	ctx := context.Background()
+	store := newStubStatsStore()
+	s := newTestStatsService(store)
+
+	// Flushed: player 3 hits, 1 miss
+	s.Hit(CacheTypePlayer, 3)
+	s.Miss(CacheTypePlayer, 1)
+	if err := s.Flush(ctx); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	// Pending: player 1 miss, results 1 hit without a stored row
+	s.Miss(CacheTypePlayer, 1)
+	s.Hit(CacheTypeResults, 1)
+
+	resp, err := s.GetStats(ctx)
+	if err != nil {
+		t.Fatalf("GetStats: %v", err)
+	}
+	if len(resp.Stats) != 2 {
+		t.Fatalf("Stats: got %d cache types, want 2", len(resp.Stats))
+	}
+
+	player, results := resp.Stats[0], resp.Stats[1]
+	if player.CacheType != CacheTypePlayer || results.CacheType != CacheTypeResults {
+		t.Fatalf("Order: got %s, %s, want player, results", player.CacheType, results.CacheType)
+	}
+	if player.Hits != 3 || player.Misses != 2 || player.HitRatio != 0.6 {
+		t.Errorf("Player: got %d hits, %d misses, ratio %v, want 3, 2, 0.6", player.Hits, player.Misses, player.HitRatio)
+	}
+	if results.Hits != 1 || results.HitRatio != 1 {
+		t.Errorf("Results: got %d hits, ratio %v, want 1, 1", results.Hits, results.HitRatio)
+	}
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/msvens/mchess/internal/config"
+	"github.com/msvens/mchess/internal/model"
+	"github.com/msvens/mchess/internal/repository"
+	"github.com/msvens/mchess/internal/upstream"
+)
+
+// tournamentSettleTime is how long after its end date a tournament is still
+// considered running, to pick up late corrections from the organisers
+const tournamentSettleTime = 7 * 24 * time.Hour
+
+// TournamentService handles tournament structure lookups with caching
+type TournamentService struct {
+	repo     *repository.TournamentRepository
+	upstream *upstream.Client
+	stats    *StatsService
+	cacheTTL time.Duration
+	batch    config.BatchConfig
+}
+
+// NewTournamentService creates a new tournament service
+func NewTournamentService(repo *repository.TournamentRepository, client *upstream.Client, stats *StatsService, cfg *config.Config) *TournamentService {
+	return &TournamentService{
+		repo:     repo,
+		upstream: client,
+		stats:    stats,
+		cacheTTL: cfg.Cache.TournamentTTL,
+		batch:    cfg.Batch,
+	}
+}
+
+// GetTournament retrieves a tournament by ID, checking cache first then upstream
+func (s *TournamentService) GetTournament(ctx context.Context, tournamentID int) (*model.Tournament, error) {
+	return decodeTournament(s.GetTournamentRaw(ctx, tournamentID))
+}
+
+// GetTournamentRaw retrieves a tournament by ID as the upstream JSON
+func (s *TournamentService) GetTournamentRaw(ctx context.Context, tournamentID int) ([]byte, error) {
+	return s.get(ctx, repository.TournamentLookupTournament, tournamentID, s.upstream.GetTournamentRaw)
+}
+
+// MaxBatchSize returns the maximum number of IDs in a batch request (0 = unlimited)
+func (s *TournamentService) MaxBatchSize() int {
+	return s.batch.MaxSize
+}
+
+// GetTournaments retrieves multiple tournaments in batch. Tournaments that
+// could not be retrieved, including those not reached before the request
+// deadline, are reported as errors.
+func (s *TournamentService) GetTournaments(ctx context.Context, tournamentIDs []int) (*model.TournamentsResponse, error) {
+	tournaments, failed := getBatch(ctx, s.batch, CacheTypeTournament, tournamentIDs, func(ctx context.Context, tournamentID int) (model.Tournament, error) {
+		tournament, err := s.GetTournament(ctx, tournamentID)
+		if err != nil {
+			return model.Tournament{}, err
+		}
+		return *tournament, nil
+	})
+
+	return &model.TournamentsResponse{Tournaments: tournaments, Errors: failed}, nil
+}
+
+// GetTournamentFromGroup retrieves the tournament a group belongs to
+func (s *TournamentService) GetTournamentFromGroup(ctx context.Context, groupID int) (*model.Tournament, error) {
+	return decodeTournament(s.GetTournamentFromGroupRaw(ctx, groupID))
+}
+
+// GetTournamentFromGroupRaw retrieves the tournament a group belongs to as
+// the upstream JSON
+func (s *TournamentService) GetTournamentFromGroupRaw(ctx context.Context, groupID int) ([]byte, error) {
+	return s.get(ctx, repository.TournamentLookupGroup, groupID, s.upstream.GetTournamentFromGroupRaw)
+}
+
+// GetTournamentFromClass retrieves the tournament a class belongs to
+func (s *TournamentService) GetTournamentFromClass(ctx context.Context, classID int) (*model.Tournament, error) {
+	return decodeTournament(s.GetTournamentFromClassRaw(ctx, classID))
+}
+
+// GetTournamentFromClassRaw retrieves the tournament a class belongs to as
+// the upstream JSON
+func (s *TournamentService) GetTournamentFromClassRaw(ctx context.Context, classID int) ([]byte, error) {
+	return s.get(ctx, repository.TournamentLookupClass, classID, s.upstream.GetTournamentFromClassRaw)
+}
+
+// get implements the cache → upstream → store flow shared by all lookup
+// types. The upstream JSON is cached and returned as is; it is only decoded
+// to decide the TTL.
+func (s *TournamentService) get(ctx context.Context, lookupType string, id int,
+	fetch func(context.Context, int) ([]byte, error)) ([]byte, error) {
+
+	// Check cache first
+	cached, fetchedAt, err := s.repo.Get(ctx, lookupType, id)
+	if err != nil {
+		slog.Error("Cache lookup failed", "error", err, "lookup", lookupType, "id", id)
+		// Continue to upstream on cache error
+	}
+	if cached != nil {
+		slog.Debug("Cache hit", "lookup", lookupType, "id", id)
+		s.stats.Hit(CacheTypeTournament, 1)
+		cacheHit(ctx, fetchedAt)
+		return cached, nil
+	}
+
+	slog.Debug("Cache miss, fetching from upstream", "lookup", lookupType, "id", id)
+	s.stats.Miss(CacheTypeTournament, 1)
+	cacheMiss(ctx)
+	s.stats.UpstreamCall(CacheTypeTournament, 1)
+
+	data, err := fetch(ctx, id)
+	if err != nil {
+		if stale := s.stale(ctx, lookupType, id, err); stale != nil {
+			return stale, nil
+		}
+		return nil, fmt.Errorf("upstream fetch: %w", err)
+	}
+
+	tournament, err := decodeTournament(data, nil)
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := s.determineTTL(tournament)
+
+	if err := s.repo.Save(ctx, lookupType, id, tournament, data, expiresAt); err != nil {
+		slog.Error("Failed to cache tournament", "error", err, "lookup", lookupType, "id", id)
+		// Continue even if caching fails
+	}
+
+	return data, nil
+}
+
+// stale returns an expired cache entry to serve in place of the upstream
+// error err, or nil if err does not mean upstream is unavailable or there
+// is no entry
+func (s *TournamentService) stale(ctx context.Context, lookupType string, id int, err error) []byte {
+	if !errors.Is(err, upstream.ErrUnavailable) {
+		return nil
+	}
+
+	data, fetchedAt, staleErr := s.repo.GetStale(ctx, lookupType, id)
+	if staleErr != nil {
+		slog.Error("Stale cache lookup failed", "error", staleErr, "lookup", lookupType, "id", id)
+		return nil
+	}
+	if data == nil {
+		return nil
+	}
+
+	serveStale(ctx, CacheTypeTournament, fetchedAt, err, "lookup", lookupType, "id", id)
+	return data
+}
+
+// decodeTournament decodes tournament JSON returned with err by a raw lookup
+func decodeTournament(data []byte, err error) (*model.Tournament, error) {
+	tournament, err := decodeUpstream[model.Tournament](data, err)
+	if err != nil {
+		return nil, err
+	}
+	return &tournament, nil
+}
+
+// determineTTL calculates the cache expiration time for a tournament
+func (s *TournamentService) determineTTL(tournament *model.Tournament) *time.Time {
+	now := time.Now()
+
+	// Finished tournaments: never expire
+	if tournamentFinished(tournament, now) {
+		return nil
+	}
+
+	// Running or coming tournaments: use configured TTL
+	expires := now.Add(s.cacheTTL)
+	return &expires
+}
+
+// tournamentFinished reports whether a tournament is over and its structure
+// will no longer change. Upstream does not document the values of State, so
+// a tournament is only treated as finished once its end date has passed.
+func tournamentFinished(tournament *model.Tournament, now time.Time) bool {
+	if tournament.End == nil || tournament.End.IsZero() {
+		return false
+	}
+	return tournament.End.Add(tournamentSettleTime).Before(now)
+}
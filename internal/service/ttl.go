@@ -0,0 +1,106 @@
+package service
+
+import (
+	"time"
+
+	"github.com/msvens/mchess/internal/config"
+)
+
+// TTLPolicy decides when cached monthly rating data (players and rating
+// lists) expires
+type TTLPolicy interface {
+	// Expiry returns when data for the month of ratingDate, fetched at
+	// now, expires, or nil if it never does
+	Expiry(ratingDate, now time.Time) *time.Time
+}
+
+// PublicationTTL expires current-month ratings around the days new rating
+// lists are published:
+//
+//   - Past months never expire, ratings are immutable once the month ends
+//   - Entries expire at the start of each publication day, and again right
+//     after it, in case the list was published after they were fetched
+//   - From a publication day until Window after it, entries expire after TTL
+//   - Between publications, entries expire after MidMonthTTL
+type PublicationTTL struct {
+	TTL             time.Duration
+	MidMonthTTL     time.Duration // 0 = TTL
+	PublicationDays []int         // Days of month lists are published, in now's location
+	Window          time.Duration
+}
+
+// NewTTLPolicy creates the TTL policy for a resource's rating TTL settings
+func NewTTLPolicy(cfg config.RatingTTLConfig) TTLPolicy {
+	p := PublicationTTL{
+		TTL:         cfg.TTL,
+		MidMonthTTL: cfg.MidMonthTTL,
+		Window:      cfg.PublicationWindow,
+	}
+	for _, day := range []int{cfg.LaskPublicationDay, cfg.FidePublicationDay} {
+		if day > 0 {
+			p.PublicationDays = append(p.PublicationDays, day)
+		}
+	}
+	return p
+}
+
+// Expiry implements TTLPolicy
+func (p PublicationTTL) Expiry(ratingDate, now time.Time) *time.Time {
+	// Historical data: never expires
+	currentMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if ratingDate.Before(currentMonth) {
+		return nil
+	}
+
+	ttl := p.TTL
+	if p.MidMonthTTL > 0 && !p.nearPublication(now) {
+		ttl = p.MidMonthTTL
+	}
+	expires := now.Add(ttl)
+
+	// Never outlive the next publication
+	if next := p.nextPublication(now); !next.IsZero() && next.Before(expires) {
+		expires = next
+	}
+	return &expires
+}
+
+// nearPublication reports whether now is on a publication day or within
+// Window after it
+func (p PublicationTTL) nearPublication(now time.Time) bool {
+	for _, start := range p.publicationDays(now, -1, 0) {
+		if !now.Before(start) && now.Before(start.AddDate(0, 0, 1).Add(p.Window)) {
+			return true
+		}
+	}
+	return false
+}
+
+// nextPublication returns the first start or end of a publication day
+// after now, or the zero time if there are no publication days
+func (p PublicationTTL) nextPublication(now time.Time) time.Time {
+	var next time.Time
+	for _, start := range p.publicationDays(now, 0, 1) {
+		for _, t := range []time.Time{start, start.AddDate(0, 0, 1)} {
+			if t.After(now) && (next.IsZero() || t.Before(next)) {
+				next = t
+			}
+		}
+	}
+	return next
+}
+
+// publicationDays returns the start of each publication day in the months
+// from now's month plus first to now's month plus last. Days past the end
+// of a month fall on its last day.
+func (p PublicationTTL) publicationDays(now time.Time, first, last int) []time.Time {
+	var days []time.Time
+	for i := first; i <= last; i++ {
+		month := time.Date(now.Year(), now.Month()+time.Month(i), 1, 0, 0, 0, 0, now.Location())
+		lastDay := month.AddDate(0, 1, -1).Day()
+		for _, day := range p.PublicationDays {
+			days = append(days, month.AddDate(0, 0, min(day, lastDay)-1))
+		}
+	}
+	return days
+}
@@ -0,0 +1,54 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/msvens/mchess/internal/service"
+)
+
+func TestPublicationTTL(t *testing.T) {
+	date := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2024, month, day, hour, 0, 0, 0, time.UTC)
+	}
+	// FIDE on the 1st, LASK on the 5th
+	policy := service.PublicationTTL{
+		TTL:             24 * time.Hour,
+		MidMonthTTL:     7 * 24 * time.Hour,
+		PublicationDays: []int{1, 5},
+		Window:          72 * time.Hour,
+	}
+
+	tests := []struct {
+		name   string
+		policy service.PublicationTTL
+		now    time.Time
+		want   time.Time // zero = never expires
+	}{
+		{"HistoricalMonth_NeverExpires", policy, date(6, 15, 12), time.Time{}},
+		{"AfterPublication_TTL", policy, date(6, 3, 12), date(6, 4, 12)},
+		{"BeforePublication_ExpiresAtPublication", policy, date(6, 4, 18), date(6, 5, 0)},
+		{"OnPublicationDay_ExpiresRightAfter", policy, date(6, 5, 10), date(6, 6, 0)},
+		{"MidMonth_MidMonthTTL", policy, date(6, 15, 12), date(6, 22, 12)},
+		{"EndOfMonth_ExpiresAtPublication", policy, date(6, 28, 12), date(7, 1, 0)},
+		{"PublicationDayPastMonthEnd_LastDay", service.PublicationTTL{TTL: time.Hour, MidMonthTTL: 7 * 24 * time.Hour, PublicationDays: []int{31}},
+			date(2, 27, 12), date(2, 29, 0)},
+		{"NoPublicationDays_TTL", service.PublicationTTL{TTL: 24 * time.Hour}, date(6, 4, 18), date(6, 5, 18)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ratingDate := time.Date(tt.now.Year(), tt.now.Month(), 1, 0, 0, 0, 0, time.UTC)
+			if tt.want.IsZero() {
+				ratingDate = ratingDate.AddDate(0, -1, 0)
+			}
+
+			got := tt.policy.Expiry(ratingDate, tt.now)
+			switch {
+			case tt.want.IsZero() && got != nil:
+				t.Errorf("Expiry: got %v, want never", *got)
+			case !tt.want.IsZero() && (got == nil || !got.Equal(tt.want)):
+				t.Errorf("Expiry: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/msvens/mchess/internal/config"
+	"github.com/msvens/mchess/internal/metrics"
+	"github.com/msvens/mchess/internal/schedule"
+	"github.com/msvens/mchess/internal/upstream"
+)
+
+var cacheWarmItems = metrics.NewCounterVec(
+	"mchess_cache_warm_items_total",
+	"Total number of items loaded by cache warming, by target and result (ok or error).",
+	"target", "result",
+)
+
+// Cache warming targets
+const (
+	WarmTargetDistrict   = "district"   // Clubs in a district
+	WarmTargetRatingList = "ratinglist" // Club rating list
+	WarmTargetPlayer     = "player"     // Player in a warmed club
+	WarmTargetTournament = "tournament" // Coming tournament
+)
+
+// WarmSummary counts the items loaded by a warming run, by target
+type WarmSummary struct {
+	Loaded map[string]int
+	Failed map[string]int
+}
+
+// record counts an item as loaded or failed
+func (s *WarmSummary) record(target string, ok bool) {
+	if !ok {
+		s.Failed[target]++
+		cacheWarmItems.Inc(target, "error")
+		return
+	}
+	s.Loaded[target]++
+	cacheWarmItems.Inc(target, "ok")
+}
+
+// Warmer loads configured players, rating lists and tournaments into the
+// cache ahead of requests, on a schedule or on demand.
+// Its upstream requests are limited to warm.rateLimit, on top of the
+// client's own limit.
+type Warmer struct {
+	organisations *OrganisationService
+	ratingLists   *RatingListService
+	players       *PlayerService
+	tournaments   *TournamentService
+	upstream      *upstream.Client
+	cfg           config.WarmConfig
+	schedule      schedule.Schedule
+	limiter       *rate.Limiter // nil = only the client's limit
+}
+
+// NewWarmer creates a new cache warmer. It fails if warm.schedule is invalid.
+func NewWarmer(organisations *OrganisationService, ratingLists *RatingListService, players *PlayerService,
+	tournaments *TournamentService, client *upstream.Client, cfg *config.Config) (*Warmer, error) {
+
+	sched, err := schedule.Parse(cfg.Warm.Schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Warmer{
+		organisations: organisations,
+		ratingLists:   ratingLists,
+		players:       players,
+		tournaments:   tournaments,
+		upstream:      client,
+		cfg:           cfg.Warm,
+		schedule:      sched,
+	}
+	if cfg.Warm.RateLimit > 0 {
+		w.limiter = rate.NewLimiter(rate.Limit(cfg.Warm.RateLimit), 1)
+	}
+	return w, nil
+}
+
+// Run warms the cache at the times in warm.schedule, until ctx is cancelled
+func (w *Warmer) Run(ctx context.Context) {
+	if w.schedule.Empty() {
+		slog.Info("Scheduled cache warming disabled")
+		return
+	}
+
+	for {
+		next := w.schedule.Next(time.Now())
+		slog.Info("Next cache warming scheduled", "at", next)
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		w.Warm(ctx)
+	}
+}
+
+// Warm loads the configured targets into the cache. Failed items are
+// logged and counted, and don't stop the run. It returns early if ctx is
+// cancelled.
+func (w *Warmer) Warm(ctx context.Context) WarmSummary {
+	summary := WarmSummary{Loaded: make(map[string]int), Failed: make(map[string]int)}
+	if len(w.cfg.Districts) == 0 && len(w.cfg.Clubs) == 0 && !w.cfg.ComingTournaments {
+		slog.Warn("Nothing to warm: set warm.districts, warm.clubs or warm.comingTournaments")
+		return summary
+	}
+	if w.limiter != nil {
+		ctx = upstream.WithLimiter(ctx, w.limiter)
+	}
+
+	start := time.Now()
+	slog.Info("Cache warming started", "districts", w.cfg.Districts, "clubs", w.cfg.Clubs,
+		"comingTournaments", w.cfg.ComingTournaments)
+
+	w.warmClubs(ctx, &summary)
+	if w.cfg.ComingTournaments {
+		w.warmComingTournaments(ctx, &summary)
+	}
+
+	if ctx.Err() != nil {
+		slog.Warn("Cache warming cancelled", "loaded", summary.Loaded, "failed", summary.Failed,
+			"duration", time.Since(start))
+		return summary
+	}
+	slog.Info("Cache warming finished", "loaded", summary.Loaded, "failed", summary.Failed,
+		"duration", time.Since(start))
+	return summary
+}
+
+// warmClubs warms the rating lists of the configured clubs and the clubs
+// in the configured districts, and the players on them
+func (w *Warmer) warmClubs(ctx context.Context, summary *WarmSummary) {
+	clubIDs := slices.Clone(w.cfg.Clubs)
+	for _, districtID := range w.cfg.Districts {
+		if ctx.Err() != nil {
+			return
+		}
+		clubs, err := w.organisations.GetClubsInDistrict(ctx, districtID)
+		summary.record(WarmTargetDistrict, err == nil)
+		if err != nil {
+			slog.Warn("Cache warming failed", "target", WarmTargetDistrict, "id", districtID, "error", err)
+			continue
+		}
+		for _, club := range clubs {
+			clubIDs = append(clubIDs, club.ID)
+		}
+	}
+	slices.Sort(clubIDs)
+	clubIDs = slices.Compact(clubIDs)
+
+	now := time.Now()
+	var memberIDs []int
+	seen := make(map[int]bool)
+	for _, clubID := range clubIDs {
+		for _, ratingType := range w.cfg.RatingTypes {
+			if ctx.Err() != nil {
+				return
+			}
+			players, err := w.ratingLists.GetClubRatingList(ctx, clubID, now, ratingType, 0)
+			summary.record(WarmTargetRatingList, err == nil)
+			if err != nil {
+				slog.Warn("Cache warming failed", "target", WarmTargetRatingList, "id", clubID,
+					"ratingType", ratingType, "error", err)
+				continue
+			}
+			for _, player := range players {
+				if !seen[player.ID] {
+					seen[player.ID] = true
+					memberIDs = append(memberIDs, player.ID)
+				}
+			}
+		}
+	}
+
+	// Fetch in batches, so a cancelled run stops between them
+	size := w.players.MaxBatchSize()
+	if size <= 0 {
+		size = len(memberIDs)
+	}
+	for batch := range slices.Chunk(memberIDs, max(size, 1)) {
+		if ctx.Err() != nil {
+			return
+		}
+		response, err := w.players.GetPlayers(ctx, batch, now)
+		if err != nil {
+			for range batch {
+				summary.record(WarmTargetPlayer, false)
+			}
+			slog.Warn("Cache warming failed", "target", WarmTargetPlayer, "players", len(batch), "error", err)
+			continue
+		}
+		for range response.Players {
+			summary.record(WarmTargetPlayer, true)
+		}
+		for _, playerErr := range response.Errors {
+			summary.record(WarmTargetPlayer, false)
+			slog.Warn("Cache warming failed", "target", WarmTargetPlayer, "id", playerErr.ID, "error", playerErr.Error)
+		}
+	}
+}
+
+// warmComingTournaments warms the coming tournaments. Their result tables
+// are left alone: until a tournament starts they are empty and expire after
+// cache.resultsTtl, long before anyone looks at them.
+func (w *Warmer) warmComingTournaments(ctx context.Context, summary *WarmSummary) {
+	if ctx.Err() != nil {
+		return
+	}
+	coming, err := w.upstream.GetComingTournaments(ctx)
+	if err != nil {
+		summary.record(WarmTargetTournament, false)
+		slog.Warn("Cache warming failed", "target", WarmTargetTournament, "error", err)
+		return
+	}
+
+	for _, t := range coming {
+		if ctx.Err() != nil {
+			return
+		}
+		_, err := w.tournaments.GetTournamentRaw(ctx, t.ID)
+		summary.record(WarmTargetTournament, err == nil)
+		if err != nil {
+			slog.Warn("Cache warming failed", "target", WarmTargetTournament, "id", t.ID, "error", err)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package service_test
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/msvens/mchess/internal/config"
+	"github.com/msvens/mchess/internal/repository"
+	"github.com/msvens/mchess/internal/service"
+	"github.com/msvens/mchess/internal/upstream"
+	"github.com/msvens/mchess/internal/upstream/fake"
+)
+
+func TestWarmer_Warm(t *testing.T) {
+	database := newTestDB(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	client := upstream.NewClient(server.BaseURL(), 5*time.Second, 100)
+
+	// District 1 has clubs 101 and 102, so club 101 is listed twice
+	cfg := &config.Config{
+		Cache: config.CacheConfig{
+			TTL:             24 * time.Hour,
+			TournamentTTL:   time.Hour,
+			ResultsTTL:      time.Hour,
+			OrganisationTTL: 24 * time.Hour,
+		},
+		Batch: config.BatchConfig{MaxSize: 2, Workers: 2},
+		Warm: config.WarmConfig{
+			Districts:         []int{1},
+			Clubs:             []int{101, 201},
+			RatingTypes:       []int{1, 6},
+			ComingTournaments: true,
+		},
+	}
+
+	warmer, err := service.NewWarmer(
+		service.NewOrganisationService(repository.NewOrganisationRepository(database.DB), client, nil, cfg),
+		service.NewRatingListService(repository.NewRatingListRepository(database.DB), client, nil, cfg),
+		service.NewPlayerService(repository.NewPlayerRepository(database.DB), client, nil, cfg),
+		service.NewTournamentService(repository.NewTournamentRepository(database.DB), client, nil, cfg),
+		client, cfg)
+	if err != nil {
+		t.Fatalf("NewWarmer: %v", err)
+	}
+
+	assertCounts := func(t *testing.T, kind string, got, want map[string]int) {
+		t.Helper()
+		if !maps.Equal(got, want) {
+			t.Errorf("%s: got %v, want %v", kind, got, want)
+		}
+	}
+
+	t.Run("FansOut", func(t *testing.T) {
+		summary := warmer.Warm(context.Background())
+
+		// Clubs 101, 102 and 201 with 2 rating types each, and the 5
+		// players on them; tournaments 6058 and 6100
+		assertCounts(t, "Loaded", summary.Loaded, map[string]int{
+			service.WarmTargetDistrict:   1,
+			service.WarmTargetRatingList: 6,
+			service.WarmTargetPlayer:     5,
+			service.WarmTargetTournament: 2,
+		})
+		assertCounts(t, "Failed", summary.Failed, map[string]int{})
+
+		// Players on several rating lists are fetched once
+		if n := server.Requests("/player/"); n != 5 {
+			t.Errorf("Player requests: got %d, want 5", n)
+		}
+		if n := server.Requests("/ratinglist/club/101/"); n != 2 {
+			t.Errorf("Club 101 rating list requests: got %d, want 2", n)
+		}
+		// Result tables of coming tournaments would expire before they start
+		if n := server.Requests("/tournamentresults/"); n != 0 {
+			t.Errorf("Result requests: got %d, want 0", n)
+		}
+	})
+
+	t.Run("CountsFailures", func(t *testing.T) {
+		clearTestDB(t, database)
+		server.Reset()
+		server.Fail("/organisation/district/clubs/", http.StatusServiceUnavailable, 0)
+		server.Fail("/player/500004/", http.StatusNotFound, 0)
+		server.Fail("/tournament/tournament/id/6100", http.StatusServiceUnavailable, 0)
+
+		summary := warmer.Warm(context.Background())
+
+		// Without district 1, only clubs 101 and 201 and their 4 players
+		assertCounts(t, "Loaded", summary.Loaded, map[string]int{
+			service.WarmTargetRatingList: 4,
+			service.WarmTargetPlayer:     3,
+			service.WarmTargetTournament: 1,
+		})
+		assertCounts(t, "Failed", summary.Failed, map[string]int{
+			service.WarmTargetDistrict:   1,
+			service.WarmTargetPlayer:     1,
+			service.WarmTargetTournament: 1,
+		})
+	})
+}
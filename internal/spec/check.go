@@ -0,0 +1,202 @@
+package spec
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/msvens/mchess/internal/model"
+)
+
+// Model maps an upstream schema to the model type it is decoded into
+type Model struct {
+	Schema string
+	Type   reflect.Type
+}
+
+// Models lists every upstream schema and its model type
+var Models = []Model{
+	{"PlayerInfoDto", reflect.TypeOf(model.PlayerInfo{})},
+	{"MemberFIDERatingDTO", reflect.TypeOf(model.EloRating{})},
+	{"MemberLASKRatingDTO", reflect.TypeOf(model.LaskRating{})},
+	{"FederationDTO", reflect.TypeOf(model.Federation{})},
+	{"DistrictDTO", reflect.TypeOf(model.District{})},
+	{"ClubDTO", reflect.TypeOf(model.Club{})},
+	{"DistrictMembershipDTO", reflect.TypeOf(model.DistrictMembership{})},
+	{"RegistrationYear", reflect.TypeOf(model.RegistrationYear{})},
+	{"TournamentDto", reflect.TypeOf(model.Tournament{})},
+	{"TournamentClassDto", reflect.TypeOf(model.TournamentClass{})},
+	{"TournamentClassGroupDto", reflect.TypeOf(model.TournamentClassGroup{})},
+	{"LocalTime", reflect.TypeOf(model.LocalTime{})},
+	{"PrizeCategoryDto", reflect.TypeOf(model.PrizeCategory{})},
+	{"RoundDto", reflect.TypeOf(model.Round{})},
+	{"TournamentSearchAnswerDto", reflect.TypeOf(model.TournamentSearchAnswer{})},
+	{"TournamentEndResultDto", reflect.TypeOf(model.TournamentEndResult{})},
+	{"TeamTournamentEndResultDto", reflect.TypeOf(model.TeamTournamentEndResult{})},
+	{"TournamentRoundResultDto", reflect.TypeOf(model.TournamentRoundResult{})},
+	{"GameDto", reflect.TypeOf(model.Game{})},
+	{"TeamRegistrationDto", reflect.TypeOf(model.TeamRegistration{})},
+	{"TeamRegistrationPlayerDto", reflect.TypeOf(model.TeamRegistrationPlayer{})},
+}
+
+// Issue is a difference between a schema and its model type
+type Issue struct {
+	Schema  string
+	Field   string // JSON property name ("" for the schema itself)
+	Problem string
+}
+
+func (i Issue) String() string {
+	if i.Field == "" {
+		return fmt.Sprintf("%s: %s", i.Schema, i.Problem)
+	}
+	return fmt.Sprintf("%s.%s: %s", i.Schema, i.Field, i.Problem)
+}
+
+var dateType = reflect.TypeOf(model.Date{})
+
+// Check compares the models with the spec schemas. It reports properties
+// missing from a model, model fields the schema doesn't have (usually a
+// renamed property), mismatched types, and schemas without a model.
+func Check(s *Spec, models []Model) []Issue {
+	var issues []Issue
+
+	types := make(map[string]reflect.Type, len(models))
+	for _, m := range models {
+		types[m.Schema] = m.Type
+	}
+
+	for _, m := range models {
+		schema, ok := s.Schema(m.Schema)
+		if !ok {
+			issues = append(issues, Issue{Schema: m.Schema, Problem: "schema not in spec"})
+			continue
+		}
+		issues = append(issues, checkModel(m, schema, types)...)
+	}
+
+	for _, name := range sortedKeys(s.Components.Schemas) {
+		if _, ok := types[name]; !ok {
+			issues = append(issues, Issue{Schema: name, Problem: "no model for schema"})
+		}
+	}
+
+	return issues
+}
+
+// checkModel compares one model type with its schema
+func checkModel(m Model, schema *Schema, types map[string]reflect.Type) []Issue {
+	var issues []Issue
+	fields := jsonFields(m.Type)
+
+	for _, name := range sortedKeys(schema.Properties) {
+		field, ok := fields[name]
+		if !ok {
+			issues = append(issues, Issue{Schema: m.Schema, Field: name, Problem: "missing from " + m.Type.Name()})
+			continue
+		}
+		if problem := checkType(field.Type, schema.Properties[name], types); problem != "" {
+			issues = append(issues, Issue{Schema: m.Schema, Field: name, Problem: problem})
+		}
+	}
+
+	for _, name := range sortedKeys(fields) {
+		if _, ok := schema.Properties[name]; !ok {
+			issues = append(issues, Issue{
+				Schema:  m.Schema,
+				Field:   name,
+				Problem: fmt.Sprintf("%s.%s is not in the schema", m.Type.Name(), fields[name].Name),
+			})
+		}
+	}
+
+	return issues
+}
+
+// checkType returns why a Go type can't hold values of a schema, or ""
+func checkType(t reflect.Type, schema *Schema, types map[string]reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if ref := schema.RefName(); ref != "" {
+		want, ok := types[ref]
+		if !ok {
+			return fmt.Sprintf("refers to %s, which has no model", ref)
+		}
+		if t != want {
+			return fmt.Sprintf("is %s, want %s for %s", t, want, ref)
+		}
+		return ""
+	}
+
+	ok := false
+	switch schema.Type {
+	case "integer":
+		switch t.Kind() {
+		case reflect.Int, reflect.Int32, reflect.Int64:
+			ok = true
+		}
+	case "number":
+		ok = t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64
+	case "string":
+		ok = t.Kind() == reflect.String || (schema.Format == "date-time" && t == dateType)
+	case "boolean":
+		ok = t.Kind() == reflect.Bool
+	case "array":
+		if t.Kind() != reflect.Slice || schema.Items == nil {
+			break
+		}
+		if problem := checkType(t.Elem(), schema.Items, types); problem != "" {
+			return "items " + problem
+		}
+		ok = true
+	default:
+		return fmt.Sprintf("has unsupported schema type %q", schema.Type)
+	}
+
+	if !ok {
+		return fmt.Sprintf("is %s, want %s", t, schemaType(schema))
+	}
+	return ""
+}
+
+// jsonFields returns the struct fields of t by JSON name. Fields named
+// with a leading underscore, like _cached, are mchess additions to the
+// upstream DTOs and are left out.
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" || strings.HasPrefix(name, "_") {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f
+	}
+	return fields
+}
+
+// schemaType describes a schema type for issue messages
+func schemaType(s *Schema) string {
+	if s.Format != "" {
+		return fmt.Sprintf("%s (%s)", s.Type, s.Format)
+	}
+	return s.Type
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,58 @@
+package spec_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/msvens/mchess/internal/model"
+	"github.com/msvens/mchess/internal/spec"
+)
+
+// TestModelsMatchSpec fails when the bundled spec and the models drift
+// apart, e.g. after scripts/fetch-api-docs.sh
+func TestModelsMatchSpec(t *testing.T) {
+	s, err := spec.Load("")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	for _, issue := range spec.Check(s, spec.Models) {
+		t.Error(issue)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	s, err := spec.Parse([]byte(`{"components": {"schemas": {
+		"GameDto": {"type": "object", "properties": {
+			"id": {"type": "integer", "format": "int32"},
+			"tournamentResultID": {"type": "integer", "format": "int32"},
+			"tableNr": {"type": "string"},
+			"whiteId": {"type": "integer", "format": "int32"},
+			"blackId": {"type": "integer", "format": "int32"},
+			"result": {"type": "integer", "format": "int32"},
+			"pgn": {"type": "string"},
+			"groupId": {"type": "integer", "format": "int32"},
+			"moves": {"type": "array", "items": {"type": "string"}}
+		}},
+		"OpeningDto": {"type": "object", "properties": {}}
+	}}}`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	issues := spec.Check(s, []spec.Model{{Schema: "GameDto", Type: reflect.TypeOf(model.Game{})}})
+
+	want := []string{
+		"GameDto.groupId: missing from Game",
+		"GameDto.moves: missing from Game",
+		"GameDto.tableNr: is int, want string",
+		"GameDto.groupiD: Game.GroupID is not in the schema",
+		"OpeningDto: no model for schema",
+	}
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.String())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Issues:\ngot  %q\nwant %q", got, want)
+	}
+}
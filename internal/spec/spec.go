@@ -0,0 +1,84 @@
+// Package spec reads the schack.se OpenAPI spec in api-specs/ and checks
+// our model types against its schemas.
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	apispecs "github.com/msvens/mchess/api-specs"
+)
+
+// BasePath is the prefix of the paths in the spec that upstream base URLs include
+const BasePath = "/public/api/v1"
+
+// Spec is the part of an OpenAPI 3 document needed to check models and responses
+type Spec struct {
+	Paths      map[string]map[string]*Operation `json:"paths"` // Keyed by path and method
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas"`
+	} `json:"components"`
+}
+
+// Operation is an API operation
+type Operation struct {
+	Responses map[string]*Response `json:"responses"` // Keyed by status code
+}
+
+// Response is an operation response
+type Response struct {
+	Content map[string]*MediaType `json:"content"` // Keyed by media type
+}
+
+// MediaType is the schema of a response in one media type
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema is a JSON schema. A schema with Ref refers to a named component schema.
+type Schema struct {
+	Ref        string             `json:"$ref,omitempty"`
+	Type       string             `json:"type,omitempty"`
+	Format     string             `json:"format,omitempty"`
+	Items      *Schema            `json:"items,omitempty"`
+	Properties map[string]*Schema `json:"properties,omitempty"`
+}
+
+// RefName returns the component name the schema refers to, or "" if it
+// is not a reference
+func (s *Schema) RefName() string {
+	return strings.TrimPrefix(s.Ref, "#/components/schemas/")
+}
+
+// Load reads a spec file, or returns the spec bundled from api-specs/ if
+// path is empty
+func Load(path string) (*Spec, error) {
+	if path == "" {
+		return Parse(apispecs.SchackSE)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read spec: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse decodes a spec document
+func Parse(data []byte) (*Spec, error) {
+	var s Spec
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("decode spec: %w", err)
+	}
+	if len(s.Components.Schemas) == 0 {
+		return nil, fmt.Errorf("decode spec: no component schemas")
+	}
+	return &s, nil
+}
+
+// Schema returns a component schema by name
+func (s *Spec) Schema(name string) (*Schema, bool) {
+	schema, ok := s.Components.Schemas[name]
+	return schema, ok
+}
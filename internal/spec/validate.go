@@ -0,0 +1,213 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxProblems bounds the problems reported for one response
+const maxProblems = 10
+
+// ValidationError is returned for a response that doesn't match its schema
+type ValidationError struct {
+	Endpoint string   // Path template, relative to BasePath
+	Problems []string // "$.field: problem", at most maxProblems
+}
+
+func (e *ValidationError) Error() string {
+	msg := fmt.Sprintf("response for %s does not match the spec: %s", e.Endpoint, e.Problems[0])
+	if len(e.Problems) > 1 {
+		msg += fmt.Sprintf(" (and %d more)", len(e.Problems)-1)
+	}
+	return msg
+}
+
+// Validator checks upstream response bodies against the spec
+type Validator struct {
+	routes  []route
+	schemas map[string]*Schema
+}
+
+// route is the 200 response schema of a GET path
+type route struct {
+	template string
+	segments []string
+	schema   *Schema
+}
+
+// NewValidator creates a validator for the GET operations in the spec
+func NewValidator(s *Spec) *Validator {
+	v := &Validator{schemas: s.Components.Schemas}
+	for path, methods := range s.Paths {
+		op, ok := methods["get"]
+		if !ok {
+			continue
+		}
+		resp, ok := op.Responses["200"]
+		if !ok {
+			continue
+		}
+		for _, media := range resp.Content {
+			if media.Schema == nil {
+				continue
+			}
+			template := strings.TrimPrefix(path, BasePath)
+			v.routes = append(v.routes, route{
+				template: template,
+				segments: strings.Split(strings.Trim(template, "/"), "/"),
+				schema:   media.Schema,
+			})
+			break
+		}
+	}
+	return v
+}
+
+// Validate checks a response body for a request path (relative to
+// BasePath). Paths that aren't in the spec are not checked. Properties
+// that aren't in the schema and null values are allowed.
+func (v *Validator) Validate(path string, body []byte) error {
+	if v == nil {
+		return nil
+	}
+	r, ok := v.match(path)
+	if !ok {
+		return nil
+	}
+
+	var value interface{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	err := dec.Decode(&value)
+	if err == nil && dec.Decode(new(interface{})) != io.EOF {
+		err = errors.New("data after JSON value")
+	}
+	if err != nil {
+		return &ValidationError{
+			Endpoint: r.template,
+			Problems: []string{fmt.Sprintf("$: invalid JSON (%s): %v", http.DetectContentType(body), err)},
+		}
+	}
+
+	var problems []string
+	v.check(value, r.schema, "$", &problems)
+	if len(problems) > 0 {
+		return &ValidationError{Endpoint: r.template, Problems: problems}
+	}
+	return nil
+}
+
+// match returns the route for a path. When several routes match, the one
+// with the most literal segments wins.
+func (v *Validator) match(path string) (route, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	best, bestScore := route{}, -1
+	for _, r := range v.routes {
+		if len(r.segments) != len(segments) {
+			continue
+		}
+		score := 0
+		for i, s := range r.segments {
+			if strings.HasPrefix(s, "{") {
+				continue
+			}
+			if s != segments[i] {
+				score = -1
+				break
+			}
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = r, score
+		}
+	}
+	return best, bestScore >= 0
+}
+
+// check appends the ways value doesn't match schema to problems
+func (v *Validator) check(value interface{}, schema *Schema, at string, problems *[]string) {
+	if value == nil || len(*problems) >= maxProblems {
+		return
+	}
+	if ref := schema.RefName(); ref != "" {
+		if resolved, ok := v.schemas[ref]; ok {
+			v.check(value, resolved, at, problems)
+		}
+		return
+	}
+
+	fail := func(want string) {
+		*problems = append(*problems, fmt.Sprintf("%s: want %s, got %s", at, want, jsonType(value)))
+	}
+
+	switch schema.Type {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			fail("object")
+			return
+		}
+		for _, name := range sortedKeys(schema.Properties) {
+			if field, ok := obj[name]; ok {
+				v.check(field, schema.Properties[name], at+"."+name, problems)
+			}
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			fail("array")
+			return
+		}
+		if schema.Items != nil {
+			for i, item := range items {
+				v.check(item, schema.Items, fmt.Sprintf("%s[%d]", at, i), problems)
+			}
+		}
+	case "integer":
+		if n, ok := value.(json.Number); !ok || !isInteger(n) {
+			fail("integer")
+		}
+	case "number":
+		if _, ok := value.(json.Number); !ok {
+			fail("number")
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			fail("string")
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			fail("boolean")
+		}
+	}
+}
+
+func isInteger(n json.Number) bool {
+	if _, err := n.Int64(); err == nil {
+		return true
+	}
+	f, err := n.Float64()
+	return err == nil && f == float64(int64(f))
+}
+
+// jsonType names the JSON type of a decoded value
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	}
+	return "null"
+}
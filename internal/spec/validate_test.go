@@ -0,0 +1,64 @@
+package spec_test
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/msvens/mchess/internal/spec"
+)
+
+func TestValidator(t *testing.T) {
+	s, err := spec.Load("")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	v := spec.NewValidator(s)
+
+	fixture, err := os.ReadFile("../upstream/fake/fixtures/tournaments/6058.json")
+	if err != nil {
+		t.Fatalf("Read fixture: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		body     string
+		problems []string // nil = valid
+	}{
+		{"Fixture_Valid", "/tournament/tournament/id/6058", string(fixture), nil},
+		{"UnknownFieldsAndNulls_Valid", "/player/1/date/2024-06-01", `{"id": 1, "elo": null, "newField": [1]}`, nil},
+		{"PathNotInSpec_NotChecked", "/player/batch", `<html>`, nil},
+		{"Boolean_Valid", "/organisation/club/exists/SK/1", `true`, nil},
+		{"HTML_Invalid", "/organisation/districts", `<html><body>Service unavailable</body></html>`,
+			[]string{"$: invalid JSON (text/html; charset=utf-8): invalid character '<' looking for beginning of value"}},
+		{"WrongTypes_Invalid", "/player/1/date/2024-06-01", `{"id": "1", "elo": {"rating": 2100.5}}`,
+			[]string{"$.elo.rating: want integer, got number", "$.id: want integer, got string"}},
+		{"ObjectForArray_Invalid", "/organisation/districts", `{"id": 1}`,
+			[]string{"$: want array, got object"}},
+		{"NestedArrayItem_Invalid", "/tournamentresults/table/id/1", `[{"points": 1}, {"points": "one"}]`,
+			[]string{"$[1].points: want number, got string"}},
+		// The literal "fideid" route wins over /player/{id}/date/{date}
+		{"LiteralRoute_Matched", "/player/fideid/1/date/2024-06-01", `{"id": 1}`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Validate(tt.path, []byte(tt.body))
+			if tt.problems == nil {
+				if err != nil {
+					t.Errorf("Validate: got %v, want valid", err)
+				}
+				return
+			}
+			var validationErr *spec.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Validate: got %v, want ValidationError", err)
+			}
+			if !slices.Equal(validationErr.Problems, tt.problems) {
+				t.Errorf("Problems:\ngot  %q\nwant %q", validationErr.Problems, tt.problems)
+			}
+		})
+	}
+}
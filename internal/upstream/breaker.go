@@ -0,0 +1,130 @@
+package upstream
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// BreakerPolicy controls when the circuit breaker opens.
+// The zero value disables the breaker.
+type BreakerPolicy struct {
+	FailureThreshold int           // Consecutive failures before the breaker opens (0 = disabled)
+	Cooldown         time.Duration // How long the breaker stays open before letting a probe request through
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// breaker is a consecutive-failure circuit breaker. Transport errors and 5xx
+// responses count as failures. After the cooldown a single probe request is
+// let through; its outcome closes or reopens the breaker. A nil *breaker
+// lets every request through.
+type breaker struct {
+	policy BreakerPolicy
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newBreaker(policy BreakerPolicy) *breaker {
+	if policy.FailureThreshold <= 0 {
+		return nil
+	}
+	return &breaker{policy: policy}
+}
+
+// allow returns ErrCircuitOpen if a request may not be sent now. probe
+// reports whether the request is the half-open probe. Every allowed request
+// must be followed by a call to record or release with the same probe.
+func (b *breaker) allow() (probe bool, err error) {
+	if b == nil {
+		return false, nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.policy.Cooldown {
+			return false, ErrCircuitOpen
+		}
+		b.state = breakerHalfOpen
+		fallthrough
+	case breakerHalfOpen:
+		if b.probing {
+			return false, ErrCircuitOpen
+		}
+		b.probing = true
+		return true, nil
+	}
+	return false, nil
+}
+
+// release gives up an allowed request that was never sent. A released
+// probe lets the next request probe instead.
+func (b *breaker) release(probe bool) {
+	if b == nil || !probe {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// record updates the breaker with the outcome of an allowed request.
+// Outcomes of requests allowed before the breaker opened are ignored once
+// it is open; only the probe decides whether it closes again.
+func (b *breaker) record(probe bool, err error) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if probe {
+		b.probing = false
+	} else if b.state != breakerClosed {
+		return
+	}
+
+	var upstreamErr *Error
+	switch {
+	case errors.Is(err, context.Canceled):
+		// The caller went away; says nothing about upstream
+	case errors.As(err, &upstreamErr) && upstreamErr.StatusCode == http.StatusTooManyRequests:
+		// Upstream is up but wants us to slow down, which retries do by
+		// honouring Retry-After
+	case !errors.Is(err, ErrUnavailable):
+		// Upstream answered; 4xx responses are the caller's problem, not an outage
+		if b.state != breakerClosed {
+			slog.Info("Upstream circuit breaker closed")
+		}
+		b.state = breakerClosed
+		b.failures = 0
+	default:
+		b.failures++
+		if probe || b.failures >= b.policy.FailureThreshold {
+			if b.state != breakerOpen {
+				slog.Warn("Upstream circuit breaker opened", "failures", b.failures, "cooldown", b.policy.Cooldown)
+				circuitOpens.Inc()
+			}
+			b.state = breakerOpen
+			b.openedAt = time.Now()
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package upstream
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBreaker(t *testing.T) {
+	const cooldown = 50 * time.Millisecond
+	failure := &Error{StatusCode: 503}
+
+	// open returns a breaker opened by threshold consecutive failures
+	open := func(t *testing.T) *breaker {
+		t.Helper()
+		b := newBreaker(BreakerPolicy{FailureThreshold: 3, Cooldown: cooldown})
+		for i := range 3 {
+			probe, err := b.allow()
+			if err != nil {
+				t.Fatalf("Request %d rejected before threshold: %v", i, err)
+			}
+			b.record(probe, failure)
+		}
+		return b
+	}
+
+	t.Run("OpensAfterThreshold", func(t *testing.T) {
+		b := newBreaker(BreakerPolicy{FailureThreshold: 3, Cooldown: cooldown})
+		for range 2 {
+			probe, _ := b.allow()
+			b.record(probe, failure)
+		}
+		if _, err := b.allow(); err != nil {
+			t.Fatalf("Rejected after 2 of 3 failures: %v", err)
+		}
+
+		// A success resets the count
+		b.record(false, nil)
+		for range 2 {
+			probe, _ := b.allow()
+			b.record(probe, failure)
+		}
+		if _, err := b.allow(); err != nil {
+			t.Fatalf("Rejected after reset and 2 failures: %v", err)
+		}
+		b.record(false, failure)
+		if _, err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+			t.Errorf("After 3 failures allow() = %v, want ErrCircuitOpen", err)
+		}
+	})
+
+	t.Run("IgnoresRateLimiting", func(t *testing.T) {
+		b := newBreaker(BreakerPolicy{FailureThreshold: 3, Cooldown: cooldown})
+		for range 2 {
+			probe, _ := b.allow()
+			b.record(probe, failure)
+		}
+
+		// 429s neither count as failures nor reset the count
+		for range 5 {
+			probe, err := b.allow()
+			if err != nil {
+				t.Fatalf("Rejected after 429: %v", err)
+			}
+			b.record(probe, &Error{StatusCode: 429, RetryAfter: time.Second})
+		}
+		b.record(false, failure)
+		if _, err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+			t.Errorf("After 3 failures among 429s allow() = %v, want ErrCircuitOpen", err)
+		}
+	})
+
+	t.Run("RejectsDuringCooldown", func(t *testing.T) {
+		b := open(t)
+		if _, err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+			t.Errorf("allow() = %v, want ErrCircuitOpen", err)
+		}
+	})
+
+	t.Run("SingleProbe", func(t *testing.T) {
+		b := open(t)
+		time.Sleep(cooldown)
+
+		probe, err := b.allow()
+		if err != nil || !probe {
+			t.Fatalf("After cooldown allow() = %v, %v, want probe", probe, err)
+		}
+		if _, err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+			t.Errorf("Second request during probe: allow() = %v, want ErrCircuitOpen", err)
+		}
+
+		// A request allowed before the breaker opened does not end the probe
+		b.record(false, nil)
+		b.release(false)
+		if _, err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+			t.Errorf("After non-probe outcome allow() = %v, want ErrCircuitOpen", err)
+		}
+
+		// A released or cancelled probe lets the next request probe
+		b.release(probe)
+		probe, err = b.allow()
+		if err != nil || !probe {
+			t.Fatalf("After released probe allow() = %v, %v, want probe", probe, err)
+		}
+		b.record(probe, context.Canceled)
+		if probe, err = b.allow(); err != nil || !probe {
+			t.Fatalf("After cancelled probe allow() = %v, %v, want probe", probe, err)
+		}
+	})
+
+	t.Run("ClosesOnProbeSuccess", func(t *testing.T) {
+		b := open(t)
+		time.Sleep(cooldown)
+
+		probe, _ := b.allow()
+		b.record(probe, &Error{StatusCode: 404}) // Upstream answered
+		for range 3 {
+			probe, err := b.allow()
+			if err != nil || probe {
+				t.Fatalf("After probe success allow() = %v, %v, want closed", probe, err)
+			}
+			b.record(probe, nil)
+		}
+	})
+
+	t.Run("ReopensOnProbeFailure", func(t *testing.T) {
+		b := open(t)
+		time.Sleep(cooldown)
+
+		probe, _ := b.allow()
+		b.record(probe, failure)
+		if _, err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+			t.Errorf("After probe failure allow() = %v, want ErrCircuitOpen", err)
+		}
+
+		// The cooldown starts over
+		time.Sleep(cooldown)
+		if probe, err := b.allow(); err != nil || !probe {
+			t.Errorf("After second cooldown allow() = %v, %v, want probe", probe, err)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		b := newBreaker(BreakerPolicy{})
+		for range 10 {
+			probe, err := b.allow()
+			if err != nil {
+				t.Fatalf("Disabled breaker rejected request: %v", err)
+			}
+			b.record(probe, failure)
+		}
+	})
+}
@@ -0,0 +1,184 @@
+package upstream
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Mode selects where upstream responses come from
+type Mode string
+
+const (
+	ModeLive   Mode = "live"   // Call upstream
+	ModeRecord Mode = "record" // Call upstream and save every response as a cassette
+	ModeReplay Mode = "replay" // Serve saved cassettes and never call upstream
+)
+
+// ErrNoCassette is returned in replay mode for requests that were never
+// recorded. It matches ErrUnavailable, so expired cache entries are served
+// where there are any, but it is not retried.
+var ErrNoCassette = fmt.Errorf("no recorded response: %w", ErrUnavailable)
+
+// WithCassettes records responses to, or replays them from, cassette files
+// in dir. ModeLive leaves the client unchanged.
+func WithCassettes(mode Mode, dir string) Option {
+	return func(c *Client) {
+		if mode == ModeRecord || mode == ModeReplay {
+			c.cassettes = &cassettes{mode: mode, dir: dir}
+		}
+	}
+}
+
+// cassette is a recorded upstream response
+type cassette struct {
+	Path       string      `json:"path"`
+	RecordedAt time.Time   `json:"recordedAt"`
+	Status     int         `json:"status"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body"`
+}
+
+// cassettes stores one cassette file per request path, at the request path
+// below dir with a .json suffix, so a recording can be browsed and edited
+type cassettes struct {
+	mode Mode
+	dir  string
+}
+
+// replaying reports whether responses come from cassettes rather than upstream
+func (c *cassettes) replaying() bool {
+	return c != nil && c.mode == ModeReplay
+}
+
+// record saves a response, overwriting any earlier recording for the path.
+// Failures are logged; they must not fail the request.
+func (c *cassettes) record(path string, status int, header http.Header, body []byte) {
+	if c == nil || c.mode != ModeRecord {
+		return
+	}
+
+	data, err := json.MarshalIndent(cassette{
+		Path:       path,
+		RecordedAt: time.Now().UTC(),
+		Status:     status,
+		Header:     header,
+		Body:       string(body),
+	}, "", "  ")
+	if err != nil {
+		slog.Error("Failed to encode cassette", "path", path, "error", err)
+		return
+	}
+
+	if err := writeFileAtomic(c.file(path), data); err != nil {
+		slog.Error("Failed to record cassette", "path", path, "error", err)
+		return
+	}
+	slog.Debug("Recorded cassette", "path", path, "status", status)
+}
+
+// replay returns the recorded response for a path, as send would have
+func (c *cassettes) replay(path string) ([]byte, error) {
+	data, err := os.ReadFile(c.file(path))
+	if os.IsNotExist(err) {
+		slog.Warn("No cassette for upstream request", "path", path)
+		return nil, fmt.Errorf("%s: %w", path, ErrNoCassette)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read cassette: %w", err)
+	}
+
+	var recorded cassette
+	if err := json.Unmarshal(data, &recorded); err != nil {
+		return nil, fmt.Errorf("decode cassette %s: %w", path, err)
+	}
+
+	slog.Debug("Replayed cassette", "path", path, "status", recorded.Status)
+	return checkResponse(recorded.Status, recorded.Header, []byte(recorded.Body))
+}
+
+// ServeCassettes returns a handler that serves the cassettes in dir, as
+// recorded, for requests below prefix. Requests without a cassette are
+// passed to next.
+func ServeCassettes(dir, prefix string, next http.Handler) http.Handler {
+	c := &cassettes{mode: ModeReplay, dir: dir}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, ok := strings.CutPrefix(r.URL.EscapedPath(), prefix)
+		if !ok || r.Method != http.MethodGet {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		data, err := os.ReadFile(c.file(path))
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		var recorded cassette
+		if err := json.Unmarshal(data, &recorded); err != nil {
+			http.Error(w, fmt.Sprintf("decode cassette %s: %v", path, err), http.StatusInternalServerError)
+			return
+		}
+
+		for key, values := range recorded.Header {
+			if !replayedHeader(key) {
+				continue
+			}
+			for _, v := range values {
+				w.Header().Add(key, v)
+			}
+		}
+		w.WriteHeader(recorded.Status)
+		w.Write([]byte(recorded.Body))
+	})
+}
+
+// replayedHeader reports whether ServeCassettes copies a recorded header.
+// Framing headers describe the original connection, not the replayed body.
+func replayedHeader(key string) bool {
+	switch http.CanonicalHeaderKey(key) {
+	case "Content-Length", "Transfer-Encoding", "Connection":
+		return false
+	}
+	return true
+}
+
+// file returns the cassette file for a request path. Segments that would
+// leave dir are escaped.
+func (c *cassettes) file(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, s := range segments {
+		if s == "." || s == ".." {
+			segments[i] = strings.ReplaceAll(s, ".", "%2E")
+		}
+	}
+	return filepath.Join(c.dir, filepath.Join(segments...)+".json")
+}
+
+// writeFileAtomic writes data to a temporary file and renames it into
+// place, so a reader never sees a partial cassette
+func writeFileAtomic(name string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".cassette-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
@@ -0,0 +1,97 @@
+package upstream_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/msvens/mchess/internal/upstream"
+	"github.com/msvens/mchess/internal/upstream/fake"
+)
+
+func TestCassettes(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	retry := upstream.WithRetry(upstream.RetryPolicy{MaxRetries: 2})
+
+	server := fake.NewServer()
+	defer server.Close()
+	server.Fail("/organisation/districts", http.StatusInternalServerError, 0)
+
+	recorder := upstream.NewClient(server.BaseURL(), 5*time.Second, 100, upstream.WithCassettes(upstream.ModeRecord, dir))
+	player, err := recorder.GetPlayer(ctx, 500001, "2024-06-01")
+	if err != nil {
+		t.Fatalf("Record GetPlayer: %v", err)
+	}
+	if _, err := recorder.GetDistricts(ctx); err == nil {
+		t.Fatal("Record GetDistricts: got success, want injected error")
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "player", "500001", "date", "2024-06-01.json")); err != nil {
+		t.Errorf("Cassette file: %v", err)
+	}
+
+	// Replay must not touch the network
+	server.Close()
+	replayer := upstream.NewClient(server.BaseURL(), 5*time.Second, 100, retry, upstream.WithCassettes(upstream.ModeReplay, dir))
+
+	t.Run("Recorded_ReturnsSameResponse", func(t *testing.T) {
+		replayed, err := replayer.GetPlayer(ctx, 500001, "2024-06-01")
+		if err != nil {
+			t.Fatalf("Replay GetPlayer: %v", err)
+		}
+		if replayed.ID != player.ID || replayed.LastName != player.LastName {
+			t.Errorf("Player: got %d/%s, want %d/%s", replayed.ID, replayed.LastName, player.ID, player.LastName)
+		}
+	})
+
+	t.Run("RecordedError_ReturnsSameError", func(t *testing.T) {
+		_, err := replayer.GetDistricts(ctx)
+		var upstreamErr *upstream.Error
+		if !errors.As(err, &upstreamErr) || upstreamErr.StatusCode != http.StatusInternalServerError {
+			t.Errorf("Error: got %v, want status 500", err)
+		}
+	})
+
+	t.Run("NotRecorded_ReturnsErrNoCassette", func(t *testing.T) {
+		_, err := replayer.GetPlayer(ctx, 500002, "2024-06-01")
+		if !errors.Is(err, upstream.ErrNoCassette) || !errors.Is(err, upstream.ErrUnavailable) {
+			t.Errorf("Error: got %v, want ErrNoCassette", err)
+		}
+	})
+
+	t.Run("ServeCassettes_ServesRecordedResponses", func(t *testing.T) {
+		mock := httptest.NewServer(upstream.ServeCassettes(dir, fake.BasePath, fake.NewHandler()))
+		defer mock.Close()
+
+		client := upstream.NewClient(mock.URL+fake.BasePath, 5*time.Second, 100)
+		served, err := client.GetPlayer(ctx, 500001, "2024-06-01")
+		if err != nil {
+			t.Fatalf("GetPlayer: %v", err)
+		}
+		if served.ID != player.ID {
+			t.Errorf("Player ID: got %d, want %d", served.ID, player.ID)
+		}
+
+		resp, err := http.Get(mock.URL + fake.BasePath + "/organisation/districts")
+		if err != nil {
+			t.Fatalf("Get districts: %v", err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusInternalServerError {
+			t.Errorf("Recorded error status: got %d, want 500", resp.StatusCode)
+		}
+
+		// Requests without a cassette fall through to the fixtures
+		if _, err := client.GetPlayer(ctx, 500002, "2024-06-01"); err != nil {
+			t.Errorf("Fallback GetPlayer: %v", err)
+		}
+	})
+}
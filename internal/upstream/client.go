@@ -3,13 +3,49 @@ package upstream
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"sync"
 	"time"
 
 	"golang.org/x/time/rate"
+
+	"github.com/msvens/mchess/internal/metrics"
+	"github.com/msvens/mchess/internal/spec"
+)
+
+var (
+	upstreamRequests = metrics.NewCounterVec(
+		"mchess_upstream_requests_total",
+		"Total number of upstream requests by status code (\"error\" when no response was received, \"circuit_open\" when rejected by the circuit breaker).",
+		"status",
+	)
+	upstreamRequestDuration = metrics.NewHistogramVec(
+		"mchess_upstream_request_duration_seconds",
+		"Upstream request latency, excluding rate limiter wait.",
+		nil,
+	)
+	rateLimitWait = metrics.NewHistogramVec(
+		"mchess_upstream_rate_limit_wait_seconds",
+		"Time spent waiting for the upstream rate limiter.",
+		[]float64{.001, .01, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	)
+	circuitOpens = metrics.NewCounterVec(
+		"mchess_upstream_circuit_opens_total",
+		"Total number of times the upstream circuit breaker opened.",
+	)
+	upstreamCoalesced = metrics.NewCounterVec(
+		"mchess_upstream_coalesced_total",
+		"Total number of upstream requests served by joining an identical in-flight request.",
+	)
+	upstreamRetries = metrics.NewCounterVec(
+		"mchess_upstream_retries_total",
+		"Total number of retried upstream requests.",
+	)
 )
 
 // Client handles requests to the upstream schack.se API
@@ -17,17 +53,66 @@ type Client struct {
 	baseURL    string
 	httpClient *http.Client
 	limiter    *rate.Limiter
+	retry      RetryPolicy
+	breaker    *breaker
+	cassettes  *cassettes      // Records or replays responses (nil = live)
+	drift      *driftDetector  // Compares responses with our models (nil = disabled)
+	validator  *spec.Validator // Checks responses against the API spec (nil = disabled)
+
+	mu       sync.Mutex
+	inflight map[string]*call // Requests in flight by path, shared by concurrent callers
+}
+
+// call is an upstream request shared by the callers waiting for it
+type call struct {
+	ctx     *sharedContext
+	waiters int // Guarded by Client.mu
+	done    chan struct{}
+	body    []byte
+	err     error
+}
+
+// Option configures optional Client behaviour
+type Option func(*Client)
+
+// WithRetry enables retries of failed requests using the given policy
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithCircuitBreaker enables a circuit breaker using the given policy
+func WithCircuitBreaker(policy BreakerPolicy) Option {
+	return func(c *Client) {
+		c.breaker = newBreaker(policy)
+	}
+}
+
+type limiterKey struct{}
+
+// WithLimiter returns a context whose upstream requests also wait for
+// limiter, on top of the client's own rate limit. Background jobs use it to
+// take no more than a share of the upstream rate, leaving the rest for
+// API requests.
+func WithLimiter(ctx context.Context, limiter *rate.Limiter) context.Context {
+	return context.WithValue(ctx, limiterKey{}, limiter)
 }
 
 // NewClient creates a new upstream API client
-func NewClient(baseURL string, timeout time.Duration, rateLimit int) *Client {
-	return &Client{
+func NewClient(baseURL string, timeout time.Duration, rateLimit int, opts ...Option) *Client {
+	c := &Client{
 		baseURL: baseURL,
 		httpClient: &http.Client{
 			Timeout: timeout,
 		},
-		limiter: rate.NewLimiter(rate.Limit(rateLimit), rateLimit), // allow bursts up to rateLimit
+		limiter:  rate.NewLimiter(rate.Limit(rateLimit), rateLimit), // allow bursts up to rateLimit
+		inflight: make(map[string]*call),
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
 // get performs a rate-limited GET request and decodes the response
@@ -38,19 +123,219 @@ func (c *Client) get(ctx context.Context, path string, result interface{}) error
 	}
 
 	if err := json.Unmarshal(body, result); err != nil {
-		return fmt.Errorf("decode response: %w", err)
+		return fmt.Errorf("decode response: %w: %w", ErrInvalidResponse, err)
 	}
 
 	return nil
 }
 
-// GetRaw performs a rate-limited GET request and returns raw bytes (for pass-through)
+// GetRaw performs a rate-limited GET request and returns raw bytes (for pass-through).
+// Concurrent calls for the same path share a single upstream request, so the
+// returned bytes may be shared between callers and must not be modified.
+// The shared request runs until the last caller waiting for it gives up,
+// within the latest of their deadlines and the retry budget.
 func (c *Client) GetRaw(ctx context.Context, path string) ([]byte, error) {
-	// Wait for rate limiter
-	if err := c.limiter.Wait(ctx); err != nil {
-		return nil, fmt.Errorf("rate limit wait: %w", err)
+	// The caller's own limiter is waited for before joining, as the shared
+	// request does not see the caller's context values
+	if limiter, ok := ctx.Value(limiterKey{}).(*rate.Limiter); ok {
+		if err := c.wait(ctx, limiter); err != nil {
+			return nil, err
+		}
+	}
+
+	c.mu.Lock()
+	cl, joined := c.inflight[path]
+	if !joined {
+		cl = &call{ctx: newSharedContext(c.retry.Budget), done: make(chan struct{})}
+		c.inflight[path] = cl
+	}
+	cl.waiters++
+	cl.ctx.join(ctx)
+	c.mu.Unlock()
+
+	if !joined {
+		go c.fetch(path, cl)
+	}
+
+	select {
+	case <-ctx.Done():
+		c.leave(path, cl)
+		return nil, fmt.Errorf("http request: %w", ctx.Err())
+	case <-cl.done:
+		if joined {
+			upstreamCoalesced.Inc()
+			slog.Debug("Upstream request coalesced", "path", path)
+		}
+		if cl.err != nil {
+			return nil, cl.err
+		}
+		return cl.body, nil
+	}
+}
+
+// fetch performs a shared request and hands the result to its callers
+func (c *Client) fetch(path string, cl *call) {
+	defer cl.ctx.cancel()
+
+	body, err := c.getRaw(cl.ctx, path)
+	if err == nil {
+		err = c.validate(path, body)
+	}
+	if err == nil {
+		c.drift.observe(path, body)
+	}
+
+	c.mu.Lock()
+	if c.inflight[path] == cl {
+		delete(c.inflight, path)
+	}
+	c.mu.Unlock()
+
+	cl.body, cl.err = body, err
+	if err != nil {
+		cl.body = nil
+	}
+	close(cl.done)
+}
+
+// leave removes a caller that gave up waiting for a shared request, and
+// cancels the request if no callers are left
+func (c *Client) leave(path string, cl *call) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cl.waiters--
+	if cl.waiters > 0 {
+		return
+	}
+	if c.inflight[path] == cl {
+		delete(c.inflight, path)
+	}
+	cl.ctx.cancel()
+}
+
+// sharedContext is the context of a request shared by several callers. It
+// carries none of their values, is cancelled when the last caller gives up,
+// and its deadline is the latest of the callers' deadlines, capped by the
+// retry budget. Callers without a deadline leave only the budget.
+type sharedContext struct {
+	context.Context
+	cancel context.CancelFunc
+
+	mu        sync.Mutex
+	deadline  time.Time // Latest caller deadline
+	unbounded bool      // A caller has no deadline
+}
+
+func newSharedContext(budget time.Duration) *sharedContext {
+	s := &sharedContext{}
+	if budget > 0 {
+		s.Context, s.cancel = context.WithTimeout(context.Background(), budget)
+	} else {
+		s.Context, s.cancel = context.WithCancel(context.Background())
+	}
+	return s
+}
+
+// join extends the deadline to cover a caller's context
+func (s *sharedContext) join(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deadline, ok := ctx.Deadline()
+	switch {
+	case !ok:
+		s.unbounded = true
+	case deadline.After(s.deadline):
+		s.deadline = deadline
+	}
+}
+
+// Deadline returns the earlier of the latest caller deadline and the budget
+func (s *sharedContext) Deadline() (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	budget, hasBudget := s.Context.Deadline()
+	if s.unbounded || (hasBudget && budget.Before(s.deadline)) {
+		return budget, hasBudget
+	}
+	return s.deadline, true
+}
+
+// getRaw performs a GET request, retrying failures according to the
+// client's retry policy
+func (c *Client) getRaw(ctx context.Context, path string) ([]byte, error) {
+	for attempt := 0; ; attempt++ {
+		body, err := c.do(ctx, path)
+		if err == nil {
+			return body, nil
+		}
+
+		delay, ok := c.retry.next(ctx, attempt, err)
+		if !ok {
+			return nil, err
+		}
+
+		slog.Warn("Upstream request failed, retrying", "path", path, "attempt", attempt+1, "delay", delay, "error", err)
+		upstreamRetries.Inc()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+// do performs a single rate-limited GET request, unless the circuit breaker is open.
+// In replay mode the recorded response is returned instead. The rate limit
+// wait and the request together are bounded by the client timeout.
+func (c *Client) do(ctx context.Context, path string) ([]byte, error) {
+	if c.cassettes.replaying() {
+		return c.cassettes.replay(path)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.httpClient.Timeout)
+	defer cancel()
+
+	probe, err := c.breaker.allow()
+	if err != nil {
+		upstreamRequests.Inc("circuit_open")
+		return nil, err
+	}
+
+	if err := c.wait(ctx, c.limiter); err != nil {
+		c.breaker.release(probe)
+		return nil, err
 	}
 
+	body, err := c.send(ctx, path)
+	c.breaker.record(probe, err)
+	return body, err
+}
+
+// wait waits until limiter lets a request through
+func (c *Client) wait(ctx context.Context, limiter *rate.Limiter) error {
+	start := time.Now()
+	err := limiter.Wait(ctx)
+	rateLimitWait.Observe(time.Since(start).Seconds())
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := ctx.Deadline(); ok && !errors.Is(err, context.Canceled) {
+		// The limiter gives up without waiting when the wait would outlast
+		// the deadline, and that error is not a context error
+		return fmt.Errorf("rate limit wait: %w: %w", ErrTimeout, err)
+	}
+	return fmt.Errorf("rate limit wait: %w", err)
+}
+
+// send sends a GET request and reads the response
+func (c *Client) send(ctx context.Context, path string) ([]byte, error) {
 	url := c.baseURL + path
 	slog.Debug("Upstream request", "url", url)
 
@@ -61,19 +346,34 @@ func (c *Client) GetRaw(ctx context.Context, path string) ([]byte, error) {
 
 	req.Header.Set("Accept", "application/json")
 
+	start := time.Now()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("http request: %w", err)
+		upstreamRequests.Inc("error")
+		upstreamRequestDuration.Observe(time.Since(start).Seconds())
+		return nil, &transportError{err: fmt.Errorf("http request: %w", err)}
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
+	upstreamRequestDuration.Observe(time.Since(start).Seconds())
+	upstreamRequests.Inc(strconv.Itoa(resp.StatusCode))
 	if err != nil {
-		return nil, fmt.Errorf("read response: %w", err)
+		return nil, &transportError{err: fmt.Errorf("read response: %w", err)}
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("upstream error: status=%d body=%s", resp.StatusCode, string(body))
+	c.cassettes.record(path, resp.StatusCode, resp.Header, body)
+	return checkResponse(resp.StatusCode, resp.Header, body)
+}
+
+// checkResponse returns the body of a 200 response, or an *Error for any other status
+func checkResponse(status int, header http.Header, body []byte) ([]byte, error) {
+	if status != http.StatusOK {
+		upstreamErr := &Error{StatusCode: status, Body: string(body)}
+		if status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable {
+			upstreamErr.RetryAfter = parseRetryAfter(header.Get("Retry-After"), time.Now())
+		}
+		return nil, upstreamErr
 	}
 
 	return body, nil
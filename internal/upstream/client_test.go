@@ -0,0 +1,313 @@
+package upstream_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/msvens/mchess/internal/upstream"
+	"github.com/msvens/mchess/internal/upstream/fake"
+)
+
+func TestWithLimiter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	// The client's own limit allows all requests at once
+	client := upstream.NewClient(server.URL, 5*time.Second, 100)
+	ctx := upstream.WithLimiter(context.Background(), rate.NewLimiter(20, 1))
+
+	start := time.Now()
+	for i := range 3 {
+		if _, err := client.GetRaw(ctx, fmt.Sprintf("/tournamentresults/table/id/%d", i)); err != nil {
+			t.Fatalf("GetRaw: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("3 requests at 20/s took %v, want at least 100ms", elapsed)
+	}
+}
+
+func TestRateLimitTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	// One request per second: the first uses the burst, the second would
+	// have to wait past the upstream timeout
+	client := upstream.NewClient(server.URL, 100*time.Millisecond, 1)
+	if _, err := client.GetRaw(context.Background(), "/tournamentresults/table/id/1"); err != nil {
+		t.Fatalf("First request: %v", err)
+	}
+
+	start := time.Now()
+	_, err := client.GetRaw(context.Background(), "/tournamentresults/table/id/2")
+	if !errors.Is(err, upstream.ErrTimeout) {
+		t.Errorf("Second request error = %v, want ErrTimeout", err)
+	}
+	if errors.Is(err, upstream.ErrUnavailable) {
+		t.Errorf("Second request error %v matches ErrUnavailable", err)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("Second request took %v, want it to fail without waiting", elapsed)
+	}
+}
+
+func TestGetRaw_Coalesces(t *testing.T) {
+	var hits atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		<-release
+		w.Write([]byte(`[{"id":1}]`))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := upstream.NewClient(server.URL, 5*time.Second, 100)
+	const path = "/tournamentresults/table/id/1"
+
+	// The first caller starts the request with a short deadline and a
+	// limiter in its context; neither may leak to the callers joining it
+	leaderCtx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	leaderCtx = upstream.WithLimiter(leaderCtx, rate.NewLimiter(rate.Every(time.Hour), 1))
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := client.GetRaw(leaderCtx, path)
+		leaderErr <- err
+	}()
+	for hits.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	const callers = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			body, err := client.GetRaw(context.Background(), path)
+			if err == nil && string(body) != `[{"id":1}]` {
+				err = fmt.Errorf("body %s", body)
+			}
+			errs <- err
+		}()
+	}
+
+	// Answer after the leader has given up
+	if err := <-leaderErr; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Leader error = %v, want deadline exceeded", err)
+	}
+	release <- struct{}{}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Joined caller: %v", err)
+		}
+	}
+	if got := hits.Load(); got != 1 {
+		t.Errorf("Upstream hits = %d, want 1", got)
+	}
+}
+
+func TestGetRaw_CancelledWhenCallersLeave(t *testing.T) {
+	cancelled := make(chan time.Time, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		cancelled <- time.Now()
+	}))
+	defer server.Close()
+
+	client := upstream.NewClient(server.URL, 5*time.Second, 100)
+	const path = "/tournamentresults/table/id/1"
+
+	// Two callers share the request; it runs until the later deadline
+	first, cancelFirst := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancelFirst()
+	second, cancelSecond := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancelSecond()
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for _, ctx := range []context.Context{first, second} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetRaw(ctx, path); !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("GetRaw error = %v, want deadline exceeded", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	select {
+	case at := <-cancelled:
+		if elapsed := at.Sub(start); elapsed < 200*time.Millisecond {
+			t.Errorf("Upstream request cancelled after %v, want the later deadline (200ms)", elapsed)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Upstream request not cancelled after the last caller left")
+	}
+}
+
+func TestRetry(t *testing.T) {
+	newClient := func(t *testing.T, policy upstream.RetryPolicy) (*fake.Server, *upstream.Client) {
+		t.Helper()
+		server := fake.NewServer()
+		t.Cleanup(server.Close)
+		return server, upstream.NewClient(server.BaseURL(), 5*time.Second, 100, upstream.WithRetry(policy))
+	}
+	policy := upstream.RetryPolicy{MaxRetries: 2, BaseDelay: 10 * time.Millisecond, MaxDelay: 2 * time.Second}
+
+	tests := []struct {
+		name         string
+		fault        func(*fake.Server)
+		budget       time.Duration // Retry budget, 0 = unlimited
+		wantErr      error         // nil = success
+		wantRequests int
+	}{
+		{
+			name:         "ServerError_RetriedUntilSuccess",
+			fault:        func(s *fake.Server) { s.Fail("/organisation/", http.StatusServiceUnavailable, 2) },
+			wantRequests: 3,
+		},
+		{
+			name:         "ServerError_GivesUpAfterMaxRetries",
+			fault:        func(s *fake.Server) { s.Fail("/organisation/", http.StatusBadGateway, 0) },
+			wantErr:      upstream.ErrUnavailable,
+			wantRequests: 3,
+		},
+		{
+			name:         "NotFound_NotRetried",
+			fault:        func(s *fake.Server) { s.Fail("/organisation/", http.StatusNotFound, 0) },
+			wantErr:      upstream.ErrNotFound,
+			wantRequests: 1,
+		},
+		{
+			name:         "BadRequest_NotRetried",
+			fault:        func(s *fake.Server) { s.Fail("/organisation/", http.StatusBadRequest, 0) },
+			wantErr:      upstream.ErrBadRequest,
+			wantRequests: 1,
+		},
+		{
+			name:         "RetryAfterPastMaxDelay_NotRetried",
+			fault:        func(s *fake.Server) { s.RateLimit("/organisation/", 3*time.Second, 1) },
+			wantErr:      upstream.ErrUnavailable,
+			wantRequests: 1,
+		},
+		{
+			name:         "RetryAfterPastBudget_NotRetried",
+			fault:        func(s *fake.Server) { s.RateLimit("/organisation/", time.Second, 1) },
+			budget:       500 * time.Millisecond,
+			wantErr:      upstream.ErrUnavailable,
+			wantRequests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := policy
+			p.Budget = tt.budget
+			server, client := newClient(t, p)
+			tt.fault(server)
+
+			start := time.Now()
+			_, err := client.GetFederation(context.Background())
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("GetFederation: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Error: got %v, want %v", err, tt.wantErr)
+			}
+			if n := server.Requests("/organisation/federation"); n != tt.wantRequests {
+				t.Errorf("Requests: got %d, want %d", n, tt.wantRequests)
+			}
+			if elapsed := time.Since(start); tt.wantRequests == 1 && elapsed > 200*time.Millisecond {
+				t.Errorf("Elapsed: got %v, want no wait before giving up", elapsed)
+			}
+		})
+	}
+
+	t.Run("CircuitOpen_NotRetried", func(t *testing.T) {
+		server := fake.NewServer()
+		defer server.Close()
+		client := upstream.NewClient(server.BaseURL(), 5*time.Second, 100,
+			upstream.WithRetry(policy),
+			upstream.WithCircuitBreaker(upstream.BreakerPolicy{FailureThreshold: 1, Cooldown: time.Minute}))
+		server.Fail("/organisation/", http.StatusServiceUnavailable, 0)
+
+		_, err := client.GetFederation(context.Background())
+		if !errors.Is(err, upstream.ErrCircuitOpen) {
+			t.Errorf("Error: got %v, want ErrCircuitOpen", err)
+		}
+		if n := server.Requests("/organisation/federation"); n != 1 {
+			t.Errorf("Requests: got %d, want 1", n)
+		}
+	})
+
+	t.Run("RateLimited_CircuitStaysClosed", func(t *testing.T) {
+		server := fake.NewServer()
+		defer server.Close()
+		client := upstream.NewClient(server.BaseURL(), 5*time.Second, 100,
+			upstream.WithRetry(policy),
+			upstream.WithCircuitBreaker(upstream.BreakerPolicy{FailureThreshold: 1, Cooldown: time.Minute}))
+		server.RateLimit("/organisation/", 10*time.Millisecond, 1)
+
+		if _, err := client.GetFederation(context.Background()); err != nil {
+			t.Fatalf("GetFederation: %v", err)
+		}
+		if n := server.Requests("/organisation/federation"); n != 2 {
+			t.Errorf("Requests: got %d, want 2", n)
+		}
+	})
+
+	t.Run("NoCassette_NotRetried", func(t *testing.T) {
+		client := upstream.NewClient("http://127.0.0.1:0", 5*time.Second, 100,
+			upstream.WithRetry(policy),
+			upstream.WithCassettes(upstream.ModeReplay, t.TempDir()))
+
+		start := time.Now()
+		_, err := client.GetFederation(context.Background())
+		if !errors.Is(err, upstream.ErrNoCassette) {
+			t.Errorf("Error: got %v, want ErrNoCassette", err)
+		}
+		if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+			t.Errorf("Elapsed: got %v, want no retries", elapsed)
+		}
+	})
+
+	t.Run("CallerDeadline_StopsRetrying", func(t *testing.T) {
+		// The caller's deadline is well within the upstream timeout and
+		// the retries upstream keeps failing for
+		server, client := newClient(t, upstream.RetryPolicy{MaxRetries: 20, BaseDelay: 100 * time.Millisecond, MaxDelay: 100 * time.Millisecond})
+		server.Fail("/organisation/", http.StatusServiceUnavailable, 0)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+		defer cancel()
+		if _, err := client.GetFederation(ctx); err == nil {
+			t.Fatal("GetFederation: got success, want error")
+		}
+
+		// No further requests once the caller has given up
+		requests := server.Requests("/organisation/federation")
+		time.Sleep(500 * time.Millisecond)
+		if n := server.Requests("/organisation/federation"); n != requests {
+			t.Errorf("Requests after the caller gave up: got %d, want none", n-requests)
+		}
+	})
+}
@@ -0,0 +1,280 @@
+package upstream
+
+import (
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/msvens/mchess/internal/metrics"
+	"github.com/msvens/mchess/internal/model"
+)
+
+var upstreamDrift = metrics.NewCounterVec(
+	"mchess_upstream_drift_total",
+	"Total number of upstream responses with fields unknown to our models (kind=\"unknown\") or missing from the response (kind=\"missing\").",
+	"endpoint", "kind",
+)
+
+// WithDriftDetection compares every upstream response with the model it is
+// decoded into, and logs and counts fields our models don't have (dropped
+// on decode and forwarded unnoticed by pass-through endpoints) and model
+// fields the response doesn't have. Only fields without omitempty are
+// expected in every response. false leaves the client unchanged.
+func WithDriftDetection(enabled bool) Option {
+	return func(c *Client) {
+		if enabled {
+			c.drift = &driftDetector{drift: make(map[string]*model.UpstreamDrift)}
+		}
+	}
+}
+
+// Drift returns the last observed differences per endpoint, and whether
+// drift detection is enabled
+func (c *Client) Drift() ([]model.UpstreamDrift, bool) {
+	if c.drift == nil {
+		return nil, false
+	}
+	return c.drift.snapshot(), true
+}
+
+// driftEndpoint is an upstream path template and the model its responses
+// are decoded into
+type driftEndpoint struct {
+	template string
+	model    reflect.Type
+}
+
+// driftEndpoints lists the upstream endpoints with a model. Paths are
+// matched in order, so literal segments must come before parameters.
+var driftEndpoints = []driftEndpoint{
+	{"/organisation/federation", reflect.TypeOf(model.Federation{})},
+	{"/organisation/districts", reflect.TypeOf([]model.District{})},
+	{"/organisation/district/clubs/{districtid}", reflect.TypeOf([]model.Club{})},
+	{"/organisation/club/{clubid}", reflect.TypeOf(model.Club{})},
+
+	{"/player/fideid/{id}/date/{date}", reflect.TypeOf(model.PlayerInfo{})},
+	{"/player/fornamn/{fornamn}/efternamn/{efternamn}", reflect.TypeOf([]model.PlayerInfo{})},
+	{"/player/{id}/date/{date}", reflect.TypeOf(model.PlayerInfo{})},
+
+	{"/ratinglist/federation/date/{ratingdate}/ratingtype/{ratingtype}/category/{category}", reflect.TypeOf([]model.PlayerInfo{})},
+	{"/ratinglist/district/{id}/date/{ratingdate}/ratingtype/{ratingtype}/category/{category}", reflect.TypeOf([]model.PlayerInfo{})},
+	{"/ratinglist/club/{id}/date/{ratingdate}/ratingtype/{ratingtype}/category/{category}", reflect.TypeOf([]model.PlayerInfo{})},
+
+	{"/tournament/tournament/id/{id}", reflect.TypeOf(model.Tournament{})},
+	{"/tournament/group/id/{id}", reflect.TypeOf(model.Tournament{})},
+	{"/tournament/class/id/{id}", reflect.TypeOf(model.Tournament{})},
+	{"/tournament/tournament/updated/{startdate}/{enddate}", reflect.TypeOf([]model.Tournament{})},
+	{"/tournament/tournament/updated/{startdate}/{enddate}/{districtid}", reflect.TypeOf([]model.Tournament{})},
+	{"/tournament/group/updated/{startdate}/{enddate}", reflect.TypeOf([]model.TournamentSearchAnswer{})},
+	{"/tournament/group/updated/{startdate}/{enddate}/{districtid}", reflect.TypeOf([]model.TournamentSearchAnswer{})},
+	{"/tournament/group/search/{searchWord}", reflect.TypeOf([]model.TournamentSearchAnswer{})},
+	{"/tournament/group/coming", reflect.TypeOf([]model.Tournament{})},
+	{"/tournament/group/coming/{districtid}", reflect.TypeOf([]model.Tournament{})},
+
+	{"/tournamentresults/table/id/{id}", reflect.TypeOf([]model.TournamentEndResult{})},
+	{"/tournamentresults/table/memberid/{id}", reflect.TypeOf([]model.TournamentEndResult{})},
+	{"/tournamentresults/roundresults/id/{id}", reflect.TypeOf([]model.TournamentRoundResult{})},
+	{"/tournamentresults/team/table/id/{id}", reflect.TypeOf([]model.TeamTournamentEndResult{})},
+	{"/tournamentresults/team/roundresults/id/{id}", reflect.TypeOf([]model.TournamentRoundResult{})},
+	{"/tournamentresults/team/roundresults/id/{id}/memberid/{memberid}", reflect.TypeOf([]model.TournamentRoundResult{})},
+	{"/tournamentresults/game/memberid/{id}", reflect.TypeOf([]model.Game{})},
+
+	{"/tournamentteamregistration/tournament/{id}/club/{clubid}", reflect.TypeOf(model.TeamRegistration{})},
+}
+
+// matchEndpoint returns the endpoint for a request path
+func matchEndpoint(path string) (driftEndpoint, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for _, e := range driftEndpoints {
+		template := strings.Split(strings.Trim(e.template, "/"), "/")
+		if len(template) != len(segments) {
+			continue
+		}
+		match := true
+		for i, s := range template {
+			if !strings.HasPrefix(s, "{") && s != segments[i] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return e, true
+		}
+	}
+	return driftEndpoint{}, false
+}
+
+// driftDetector keeps the last observed differences per endpoint
+type driftDetector struct {
+	mu    sync.Mutex
+	drift map[string]*model.UpstreamDrift // Keyed by endpoint template
+}
+
+// observe compares a response body with the model for its endpoint.
+// Paths without a model and bodies that aren't JSON are ignored; decoding
+// reports the latter.
+func (d *driftDetector) observe(path string, body []byte) {
+	if d == nil {
+		return
+	}
+	endpoint, ok := matchEndpoint(path)
+	if !ok {
+		return
+	}
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return
+	}
+
+	shapes := make(map[string]*shape)
+	collectShapes(value, endpoint.model, "", shapes)
+	unknown, missing := diffShapes(shapes)
+	if len(unknown) == 0 && len(missing) == 0 {
+		return
+	}
+
+	if len(unknown) > 0 {
+		upstreamDrift.Inc(endpoint.template, "unknown")
+	}
+	if len(missing) > 0 {
+		upstreamDrift.Inc(endpoint.template, "missing")
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	drift, seen := d.drift[endpoint.template]
+	if !seen {
+		drift = &model.UpstreamDrift{Endpoint: endpoint.template, FirstSeen: now}
+		d.drift[endpoint.template] = drift
+	}
+	changed := !seen || !slices.Equal(drift.UnknownFields, unknown) || !slices.Equal(drift.MissingFields, missing)
+
+	drift.Path = path
+	drift.UnknownFields = unknown
+	drift.MissingFields = missing
+	drift.Count++
+	drift.LastSeen = now
+
+	// Warn when the differences change, not on every response
+	if changed {
+		slog.Warn("Upstream schema drift", "endpoint", endpoint.template, "path", path,
+			"unknown", unknown, "missing", missing)
+	} else {
+		slog.Debug("Upstream schema drift", "endpoint", endpoint.template, "path", path)
+	}
+}
+
+// snapshot returns a copy of the observed differences, sorted by endpoint
+func (d *driftDetector) snapshot() []model.UpstreamDrift {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	result := make([]model.UpstreamDrift, 0, len(d.drift))
+	for _, drift := range d.drift {
+		result = append(result, *drift)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Endpoint < result[j].Endpoint })
+	return result
+}
+
+var dateType = reflect.TypeOf(model.Date{})
+
+// shape is the union of the keys of all objects decoded into one struct
+// field of a response
+type shape struct {
+	model reflect.Type
+	keys  map[string]bool
+}
+
+// collectShapes records the object keys in value by field path, e.g.
+// "rootClasses.groups" for the groups of every class
+func collectShapes(value interface{}, t reflect.Type, path string, shapes map[string]*shape) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Slice:
+		items, _ := value.([]interface{})
+		for _, item := range items {
+			collectShapes(item, t.Elem(), path, shapes)
+		}
+	case reflect.Struct:
+		obj, ok := value.(map[string]interface{})
+		if !ok || t == dateType {
+			return
+		}
+		s, ok := shapes[path]
+		if !ok {
+			s = &shape{model: t, keys: make(map[string]bool)}
+			shapes[path] = s
+		}
+		fields := jsonFields(t)
+		for key, v := range obj {
+			s.keys[key] = true
+			if f, ok := fields[key]; ok {
+				collectShapes(v, f.typ, joinPath(path, key), shapes)
+			}
+		}
+	}
+}
+
+// diffShapes returns the keys the models don't have and the required
+// model fields no object had, as sorted field paths
+func diffShapes(shapes map[string]*shape) (unknown, missing []string) {
+	for path, s := range shapes {
+		fields := jsonFields(s.model)
+		for key := range s.keys {
+			if _, ok := fields[key]; !ok {
+				unknown = append(unknown, joinPath(path, key))
+			}
+		}
+		for name, f := range fields {
+			if !f.omitempty && !s.keys[name] {
+				missing = append(missing, joinPath(path, name))
+			}
+		}
+	}
+	sort.Strings(unknown)
+	sort.Strings(missing)
+	return unknown, missing
+}
+
+type jsonField struct {
+	typ       reflect.Type
+	omitempty bool
+}
+
+// jsonFields returns the struct fields of t by JSON name
+func jsonFields(t reflect.Type) map[string]jsonField {
+	fields := make(map[string]jsonField, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = jsonField{typ: f.Type, omitempty: strings.Contains(opts, "omitempty")}
+	}
+	return fields
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
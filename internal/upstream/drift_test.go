@@ -0,0 +1,79 @@
+package upstream_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/msvens/mchess/internal/model"
+	"github.com/msvens/mchess/internal/upstream"
+)
+
+func TestDriftDetection(t *testing.T) {
+	ctx := context.Background()
+	bodies := map[string]string{
+		"/player/1/date/2024-06-01": `{"id": 1, "firstName": "Anna", "sex": 0, "elo": {"rating": 2100, "fideTitle": "WFM"}}`,
+		"/player/2/date/2024-06-01": `{"id": 2, "firstName": "Erik", "lastName": "Berg", "sex": 1}`,
+		"/tournament/group/coming":  `[{"id": 1, "name": "Open", "rootClasses": [{"classID": 1, "groups": [{"id": 1, "arena": "Hall"}]}]}]`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(bodies[r.URL.Path]))
+	}))
+	defer server.Close()
+
+	t.Run("Disabled_ReportsNothing", func(t *testing.T) {
+		client := upstream.NewClient(server.URL, 5*time.Second, 100, upstream.WithDriftDetection(false))
+		if _, err := client.GetPlayer(ctx, 1, "2024-06-01"); err != nil {
+			t.Fatalf("GetPlayer: %v", err)
+		}
+		if drift, enabled := client.Drift(); enabled || len(drift) != 0 {
+			t.Errorf("Drift: got %v/%v, want none and disabled", drift, enabled)
+		}
+	})
+
+	client := upstream.NewClient(server.URL, 5*time.Second, 100, upstream.WithDriftDetection(true))
+
+	t.Run("TypedDecode_ReportsUnknownAndMissingFields", func(t *testing.T) {
+		if _, err := client.GetPlayer(ctx, 1, "2024-06-01"); err != nil {
+			t.Fatalf("GetPlayer: %v", err)
+		}
+		// A response without differences keeps the last observed ones
+		if _, err := client.GetPlayer(ctx, 2, "2024-06-01"); err != nil {
+			t.Fatalf("GetPlayer: %v", err)
+		}
+
+		drift, enabled := client.Drift()
+		if !enabled || len(drift) != 1 {
+			t.Fatalf("Drift: got %d endpoints (enabled %v), want 1", len(drift), enabled)
+		}
+		d := drift[0]
+		if d.Endpoint != "/player/{id}/date/{date}" || d.Path != "/player/1/date/2024-06-01" || d.Count != 1 {
+			t.Errorf("Drift: got %s %s count %d, want /player/{id}/date/{date} for player 1 once", d.Endpoint, d.Path, d.Count)
+		}
+		if !slices.Equal(d.UnknownFields, []string{"elo.fideTitle"}) {
+			t.Errorf("Unknown fields: got %v, want [elo.fideTitle]", d.UnknownFields)
+		}
+		if !slices.Equal(d.MissingFields, []string{"lastName"}) {
+			t.Errorf("Missing fields: got %v, want [lastName]", d.MissingFields)
+		}
+	})
+
+	t.Run("PassThrough_ReportsNestedFields", func(t *testing.T) {
+		if _, err := client.GetRaw(ctx, "/tournament/group/coming"); err != nil {
+			t.Fatalf("GetRaw: %v", err)
+		}
+
+		drift, _ := client.Drift()
+		i := slices.IndexFunc(drift, func(d model.UpstreamDrift) bool { return d.Endpoint == "/tournament/group/coming" })
+		if i < 0 {
+			t.Fatalf("Drift: got %v, want /tournament/group/coming", drift)
+		}
+		if !slices.Equal(drift[i].UnknownFields, []string{"rootClasses.groups.arena"}) {
+			t.Errorf("Unknown fields: got %v, want [rootClasses.groups.arena]", drift[i].UnknownFields)
+		}
+	})
+}
@@ -0,0 +1,75 @@
+package upstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Sentinel errors for classifying upstream failures with errors.Is
+var (
+	// ErrNotFound means upstream answered 404
+	ErrNotFound = errors.New("upstream: not found")
+	// ErrBadRequest means upstream rejected the request parameters (400)
+	ErrBadRequest = errors.New("upstream: bad request")
+	// ErrUnavailable means upstream could not serve the request: no response,
+	// a 5xx or 429 status, or the circuit breaker is open
+	ErrUnavailable = errors.New("upstream: unavailable")
+	// ErrTimeout means upstream did not answer in time, or the rate limit did not allow
+	// a request before the deadline. Upstream timeouts also match ErrUnavailable.
+	ErrTimeout = errors.New("upstream: timeout")
+	// ErrInvalidResponse means the upstream response could not be decoded
+	ErrInvalidResponse = errors.New("upstream: invalid response")
+)
+
+// ErrCircuitOpen is returned without contacting upstream while the circuit breaker is open
+var ErrCircuitOpen = fmt.Errorf("circuit breaker open: %w", ErrUnavailable)
+
+// Error is returned for non-200 upstream responses
+type Error struct {
+	StatusCode int
+	Body       string        // Response body as returned by upstream
+	RetryAfter time.Duration // From the Retry-After header on 429 and 503, 0 if absent
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("upstream error: status=%d body=%s", e.StatusCode, e.Body)
+}
+
+// Is matches the sentinel error for the response status
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrUnavailable:
+		return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+	case ErrTimeout:
+		return e.StatusCode == http.StatusGatewayTimeout
+	}
+	return false
+}
+
+// transportError is returned when no complete response was received
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string { return e.err.Error() }
+func (e *transportError) Unwrap() error { return e.err }
+
+// Is matches ErrUnavailable, and ErrTimeout if the request timed out
+func (e *transportError) Is(target error) bool {
+	switch target {
+	case ErrUnavailable:
+		return true
+	case ErrTimeout:
+		var netErr net.Error
+		return errors.Is(e.err, context.DeadlineExceeded) || (errors.As(e.err, &netErr) && netErr.Timeout())
+	}
+	return false
+}
@@ -0,0 +1,189 @@
+// Package fake provides a fake of the schack.se API for tests and local
+// development (see the mock-upstream command).
+//
+// The fake serves every path in api-specs/schack-se-api-docs.json from
+// fixture files, so tests run offline against stable data. Latency, errors
+// and rate limiting (429 with Retry-After) can be injected per path.
+package fake
+
+import (
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// BasePath is the path prefix of the schack.se API
+const BasePath = "/public/api/v1"
+
+// Fault is a failure injected into requests whose path (relative to
+// BasePath) starts with Path
+type Fault struct {
+	Path       string        // Path prefix, e.g. "/player/" ("" matches every request)
+	Status     int           // Response status (0 = respond normally after Latency)
+	RetryAfter time.Duration // Retry-After header, rounded up to whole seconds
+	Latency    time.Duration // Delay before responding
+	Times      int           // Number of requests affected (0 = until Reset)
+}
+
+// Handler serves the fake schack.se API under BasePath
+type Handler struct {
+	router   chi.Router
+	fixtures *fixtures
+
+	mu       sync.Mutex
+	latency  time.Duration
+	faults   []*Fault
+	requests map[string]int
+}
+
+// NewHandler creates a handler serving the built-in fixtures
+func NewHandler() *Handler {
+	return newHandler(defaultFixtures())
+}
+
+// NewHandlerFS creates a handler serving the built-in fixtures together with
+// the fixtures in fsys, which use the same layout. Files in fsys replace
+// built-in files with the same path.
+func NewHandlerFS(fsys fs.FS) (*Handler, error) {
+	builtin, err := fs.Sub(embedded, "fixtures")
+	if err != nil {
+		return nil, err
+	}
+	f, err := loadFixtures(builtin, fsys)
+	if err != nil {
+		return nil, err
+	}
+	return newHandler(f), nil
+}
+
+func newHandler(f *fixtures) *Handler {
+	h := &Handler{
+		fixtures: f,
+		requests: make(map[string]int),
+	}
+	h.router = h.routes()
+	return h
+}
+
+// ServeHTTP applies any injected latency and faults, then serves the fixture
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, BasePath)
+	latency, fault := h.record(path)
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if fault != nil && fault.Status != 0 {
+		if fault.RetryAfter > 0 {
+			seconds := int((fault.RetryAfter + time.Second - 1) / time.Second)
+			w.Header().Set("Retry-After", strconv.Itoa(seconds))
+		}
+		http.Error(w, http.StatusText(fault.Status), fault.Status)
+		return
+	}
+
+	h.router.ServeHTTP(w, r)
+}
+
+// record counts a request and returns the latency and fault that apply to it
+func (h *Handler) record(path string) (time.Duration, *Fault) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.requests[path]++
+
+	for i, f := range h.faults {
+		if !strings.HasPrefix(path, f.Path) {
+			continue
+		}
+		fault := *f
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				h.faults = append(h.faults[:i], h.faults[i+1:]...)
+			}
+		}
+		return h.latency + fault.Latency, &fault
+	}
+
+	return h.latency, nil
+}
+
+// SetLatency delays every response by d
+func (h *Handler) SetLatency(d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.latency = d
+}
+
+// Inject adds a fault. When several faults match a request, the one
+// injected first applies.
+func (h *Handler) Inject(f Fault) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.faults = append(h.faults, &f)
+}
+
+// Fail makes the next times requests under path fail with status
+// (times 0 = until Reset)
+func (h *Handler) Fail(path string, status, times int) {
+	h.Inject(Fault{Path: path, Status: status, Times: times})
+}
+
+// RateLimit makes the next times requests under path fail with 429 and
+// the given Retry-After (times 0 = until Reset)
+func (h *Handler) RateLimit(path string, retryAfter time.Duration, times int) {
+	h.Inject(Fault{Path: path, Status: http.StatusTooManyRequests, RetryAfter: retryAfter, Times: times})
+}
+
+// Reset removes all injected latency and faults and clears the request counts
+func (h *Handler) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.latency = 0
+	h.faults = nil
+	h.requests = make(map[string]int)
+}
+
+// Requests returns the number of requests received whose path (relative
+// to BasePath) starts with prefix, including failed ones
+func (h *Handler) Requests(prefix string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	n := 0
+	for path, count := range h.requests {
+		if strings.HasPrefix(path, prefix) {
+			n += count
+		}
+	}
+	return n
+}
+
+// Server is a running fake schack.se API
+type Server struct {
+	*httptest.Server
+	*Handler
+}
+
+// NewServer starts a fake schack.se API server. Callers must Close it.
+func NewServer() *Server {
+	h := NewHandler()
+	return &Server{Server: httptest.NewServer(h), Handler: h}
+}
+
+// BaseURL returns the URL to use as upstream base URL
+func (s *Server) BaseURL() string {
+	return s.URL + BasePath
+}
@@ -0,0 +1,176 @@
+package fake_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/msvens/mchess/internal/upstream"
+	"github.com/msvens/mchess/internal/upstream/fake"
+)
+
+func newClient(t *testing.T, opts ...upstream.Option) (*fake.Server, *upstream.Client) {
+	t.Helper()
+	server := fake.NewServer()
+	t.Cleanup(server.Close)
+	return server, upstream.NewClient(server.BaseURL(), 5*time.Second, 100, opts...)
+}
+
+func TestFixtures(t *testing.T) {
+	_, client := newClient(t)
+	ctx := context.Background()
+
+	t.Run("PlayerByMemberID", func(t *testing.T) {
+		player, err := client.GetPlayer(ctx, 500001, "2024-06-01")
+		if err != nil {
+			t.Fatalf("GetPlayer: %v", err)
+		}
+		if player.LastName != "Andersson" || player.FideID != 1710001 {
+			t.Errorf("Player: got %s/%d, want Andersson/1710001", player.LastName, player.FideID)
+		}
+	})
+
+	t.Run("PlayerByFideID", func(t *testing.T) {
+		player, err := client.GetPlayerByFideID(ctx, 1710002, "2024-06-01")
+		if err != nil {
+			t.Fatalf("GetPlayerByFideID: %v", err)
+		}
+		if player.ID != 500002 {
+			t.Errorf("Player ID: got %d, want 500002", player.ID)
+		}
+	})
+
+	t.Run("UnknownPlayer_NotFound", func(t *testing.T) {
+		_, err := client.GetPlayer(ctx, 1, "2024-06-01")
+		if !errors.Is(err, upstream.ErrNotFound) {
+			t.Errorf("Error: got %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("ClubsInDistrict", func(t *testing.T) {
+		clubs, err := client.GetClubsInDistrict(ctx, 1)
+		if err != nil {
+			t.Fatalf("GetClubsInDistrict: %v", err)
+		}
+		if len(clubs) != 2 {
+			t.Errorf("Clubs: got %d, want 2", len(clubs))
+		}
+	})
+
+	t.Run("TournamentFromGroup", func(t *testing.T) {
+		tournament, err := client.GetTournamentFromGroup(ctx, 17001)
+		if err != nil {
+			t.Fatalf("GetTournamentFromGroup: %v", err)
+		}
+		if tournament.ID != 6058 {
+			t.Errorf("Tournament ID: got %d, want 6058", tournament.ID)
+		}
+	})
+
+	t.Run("DistrictRatingList_SortedByRating", func(t *testing.T) {
+		players, err := client.GetDistrictRatingList(ctx, 1, "2024-06-01", 1, 0)
+		if err != nil {
+			t.Fatalf("GetDistrictRatingList: %v", err)
+		}
+		if len(players) != 3 || players[0].ID != 500001 {
+			t.Errorf("Rating list: got %d players, want 3 starting with 500001", len(players))
+		}
+	})
+}
+
+func TestNewHandlerFS(t *testing.T) {
+	h, err := fake.NewHandlerFS(fstest.MapFS{
+		"clubs/101.json": {Data: []byte(`{"id":101,"name":"Renamed SK","districts":[{"districtid":1}]}`)},
+		"clubs/301.json": {Data: []byte(`{"id":301,"name":"Nya SK","districts":[{"districtid":3}]}`)},
+	})
+	if err != nil {
+		t.Fatalf("NewHandlerFS: %v", err)
+	}
+	server := httptest.NewServer(h)
+	defer server.Close()
+	client := upstream.NewClient(server.URL+fake.BasePath, 5*time.Second, 100)
+	ctx := context.Background()
+
+	t.Run("ReplacesBuiltInFixture", func(t *testing.T) {
+		club, err := client.GetClub(ctx, 101)
+		if err != nil {
+			t.Fatalf("GetClub: %v", err)
+		}
+		if club.Name != "Renamed SK" {
+			t.Errorf("Name: got %s, want Renamed SK", club.Name)
+		}
+		if clubs, _ := client.GetClubsInDistrict(ctx, 1); len(clubs) != 2 {
+			t.Errorf("Clubs in district 1: got %d, want 2", len(clubs))
+		}
+	})
+
+	t.Run("AddsFixture", func(t *testing.T) {
+		clubs, err := client.GetClubsInDistrict(ctx, 3)
+		if err != nil {
+			t.Fatalf("GetClubsInDistrict: %v", err)
+		}
+		if len(clubs) != 1 || clubs[0].ID != 301 {
+			t.Errorf("Clubs in district 3: got %v, want club 301", clubs)
+		}
+	})
+
+	t.Run("KeepsBuiltInFixtures", func(t *testing.T) {
+		if _, err := client.GetPlayer(ctx, 500001, "2024-06-01"); err != nil {
+			t.Errorf("GetPlayer: %v", err)
+		}
+	})
+
+	t.Run("InvalidJSON_ReturnsError", func(t *testing.T) {
+		_, err := fake.NewHandlerFS(fstest.MapFS{"clubs/1.json": {Data: []byte("{")}})
+		if err == nil {
+			t.Error("NewHandlerFS: got success, want error")
+		}
+	})
+}
+
+func TestFaults(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Error_ReturnsUnavailable", func(t *testing.T) {
+		server, client := newClient(t)
+		server.Fail("/player/", http.StatusServiceUnavailable, 1)
+
+		if _, err := client.GetPlayer(ctx, 500001, "2024-06-01"); !errors.Is(err, upstream.ErrUnavailable) {
+			t.Errorf("First request: got %v, want ErrUnavailable", err)
+		}
+		if _, err := client.GetPlayer(ctx, 500001, "2024-06-01"); err != nil {
+			t.Errorf("Second request: got %v, want success", err)
+		}
+	})
+
+	t.Run("RateLimit_RetriedAfterRetryAfter", func(t *testing.T) {
+		server, client := newClient(t, upstream.WithRetry(upstream.RetryPolicy{MaxRetries: 1, MaxDelay: 2 * time.Second}))
+		server.RateLimit("/organisation/", time.Second, 1)
+
+		start := time.Now()
+		if _, err := client.GetFederation(ctx); err != nil {
+			t.Fatalf("GetFederation: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed < time.Second {
+			t.Errorf("Elapsed: got %v, want at least Retry-After (1s)", elapsed)
+		}
+		if n := server.Requests("/organisation/federation"); n != 2 {
+			t.Errorf("Requests: got %d, want 2", n)
+		}
+	})
+
+	t.Run("Latency_TimesOut", func(t *testing.T) {
+		server, client := newClient(t)
+		server.SetLatency(time.Second)
+
+		ctx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+		if _, err := client.GetDistricts(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Error: got %v, want DeadlineExceeded", err)
+		}
+	})
+}
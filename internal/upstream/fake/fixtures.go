@@ -0,0 +1,350 @@
+package fake
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Fixture layout:
+//
+//	organisation/federation.json, organisation/districts.json
+//	clubs/{id}.json                  ClubDTO
+//	players/{id}.json                PlayerInfoDto
+//	tournaments/{id}.json            TournamentDto
+//	tournamentresults/{table,roundresults}/{groupid}.json
+//	tournamentresults/team/{table,roundresults}/{groupid}.json
+//	tournamentresults/{memberid,game}/{memberid}.json
+//	tournamentteamregistration/{tournamentid}-{clubid}.json
+//
+// District club lists, rating lists, FIDE ID and group/class lookups and
+// searches are derived from the club, player and tournament fixtures.
+//
+//go:embed fixtures
+var embedded embed.FS
+
+// fixtures holds the raw fixture documents and the indexes needed to
+// answer derived lookups
+type fixtures struct {
+	files map[string][]byte // Keyed by path without the .json suffix
+
+	players     []playerRef
+	clubs       []clubRef
+	tournaments []tournamentRef
+}
+
+// playerRef holds the player fields used to index and filter players
+type playerRef struct {
+	ID        int    `json:"id"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+	FideID    int    `json:"fideid"`
+	ClubID    int    `json:"clubId"`
+	Elo       *struct {
+		Rating      int `json:"rating"`
+		RapidRating int `json:"rapidRating"`
+		BlitzRating int `json:"blitzRating"`
+	} `json:"elo"`
+	Lask *struct {
+		Rating int `json:"rating"`
+	} `json:"lask"`
+
+	raw json.RawMessage
+}
+
+// clubRef holds the club fields used to index and filter clubs
+type clubRef struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Districts []struct {
+		DistrictID int `json:"districtid"`
+	} `json:"districts"`
+
+	raw json.RawMessage
+}
+
+// tournamentRef holds the tournament fields used to index and search tournaments
+type tournamentRef struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	LatestUpdated string `json:"latestUpdated"`
+	RootClasses   []struct {
+		ClassID int `json:"classID"`
+		Groups  []struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+		} `json:"groups"`
+	} `json:"rootClasses"`
+
+	raw json.RawMessage
+}
+
+var (
+	defaultOnce sync.Once
+	defaultSet  *fixtures
+)
+
+// defaultFixtures returns the built-in fixtures, loaded once
+func defaultFixtures() *fixtures {
+	defaultOnce.Do(func() {
+		sub, err := fs.Sub(embedded, "fixtures")
+		if err == nil {
+			defaultSet, err = loadFixtures(sub)
+		}
+		if err != nil {
+			panic(fmt.Sprintf("fake: load built-in fixtures: %v", err))
+		}
+	})
+	return defaultSet
+}
+
+// loadFixtures reads and indexes all .json files in the given file systems.
+// A file replaces any file with the same path in an earlier file system.
+func loadFixtures(fsyss ...fs.FS) (*fixtures, error) {
+	f := &fixtures{files: make(map[string][]byte)}
+
+	for _, fsys := range fsyss {
+		err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || path.Ext(name) != ".json" {
+				return err
+			}
+			data, err := fs.ReadFile(fsys, name)
+			if err != nil {
+				return err
+			}
+			if !json.Valid(data) {
+				return fmt.Errorf("%s: invalid JSON", name)
+			}
+			f.files[strings.TrimSuffix(name, ".json")] = data
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	keys := make([]string, 0, len(f.files))
+	for key := range f.files {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		name, data := key+".json", f.files[key]
+		var err error
+		switch path.Dir(key) {
+		case "players":
+			err = index(&f.players, name, data, func(p *playerRef) { p.raw = data })
+		case "clubs":
+			err = index(&f.clubs, name, data, func(c *clubRef) { c.raw = data })
+		case "tournaments":
+			err = index(&f.tournaments, name, data, func(t *tournamentRef) { t.raw = data })
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return f, nil
+}
+
+// index decodes a fixture document and appends it to refs
+func index[T any](refs *[]T, name string, data []byte, setRaw func(*T)) error {
+	var ref T
+	if err := json.Unmarshal(data, &ref); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	setRaw(&ref)
+	*refs = append(*refs, ref)
+	return nil
+}
+
+// file returns a fixture document by key
+func (f *fixtures) file(key string) ([]byte, bool) {
+	data, ok := f.files[key]
+	return data, ok
+}
+
+// player returns the player matching the predicate
+func (f *fixtures) player(match func(p *playerRef) bool) (json.RawMessage, bool) {
+	for i := range f.players {
+		if match(&f.players[i]) {
+			return f.players[i].raw, true
+		}
+	}
+	return nil, false
+}
+
+// searchPlayers returns players whose names start with the given names,
+// ignoring case
+func (f *fixtures) searchPlayers(firstName, lastName string) []json.RawMessage {
+	result := []json.RawMessage{}
+	for _, p := range f.players {
+		if hasPrefixFold(p.FirstName, firstName) && hasPrefixFold(p.LastName, lastName) {
+			result = append(result, p.raw)
+		}
+	}
+	return result
+}
+
+// ratingList returns the players matching the predicate, sorted by
+// descending rating of the given type
+func (f *fixtures) ratingList(ratingType int, match func(p *playerRef) bool) []json.RawMessage {
+	var players []playerRef
+	for _, p := range f.players {
+		if match(&p) {
+			players = append(players, p)
+		}
+	}
+	sort.SliceStable(players, func(i, j int) bool {
+		return players[i].rating(ratingType) > players[j].rating(ratingType)
+	})
+
+	result := make([]json.RawMessage, len(players))
+	for i, p := range players {
+		result[i] = p.raw
+	}
+	return result
+}
+
+// rating returns the player's rating for a rating list type
+// (1 = standard, 6 = rapid, 7 = blitz, otherwise LASK)
+func (p *playerRef) rating(ratingType int) int {
+	switch {
+	case ratingType == 1 && p.Elo != nil:
+		return p.Elo.Rating
+	case ratingType == 6 && p.Elo != nil:
+		return p.Elo.RapidRating
+	case ratingType == 7 && p.Elo != nil:
+		return p.Elo.BlitzRating
+	case p.Lask != nil:
+		return p.Lask.Rating
+	}
+	return 0
+}
+
+// club returns a club by ID
+func (f *fixtures) club(id int) (*clubRef, bool) {
+	for i := range f.clubs {
+		if f.clubs[i].ID == id {
+			return &f.clubs[i], true
+		}
+	}
+	return nil, false
+}
+
+// districtClubs returns the clubs that are members of a district
+func (f *fixtures) districtClubs(districtID int) []json.RawMessage {
+	result := []json.RawMessage{}
+	for _, c := range f.clubs {
+		if c.inDistrict(districtID) {
+			result = append(result, c.raw)
+		}
+	}
+	return result
+}
+
+// inDistrict reports whether the club is a member of a district
+func (c *clubRef) inDistrict(districtID int) bool {
+	for _, d := range c.Districts {
+		if d.DistrictID == districtID {
+			return true
+		}
+	}
+	return false
+}
+
+// tournament returns the tournament matching the predicate
+func (f *fixtures) tournament(match func(t *tournamentRef) bool) (json.RawMessage, bool) {
+	for i := range f.tournaments {
+		if match(&f.tournaments[i]) {
+			return f.tournaments[i].raw, true
+		}
+	}
+	return nil, false
+}
+
+// hasClass reports whether the tournament has a class with the given ID
+func (t *tournamentRef) hasClass(classID int) bool {
+	for _, c := range t.RootClasses {
+		if c.ClassID == classID {
+			return true
+		}
+	}
+	return false
+}
+
+// hasGroup reports whether the tournament has a group with the given ID
+func (t *tournamentRef) hasGroup(groupID int) bool {
+	for _, c := range t.RootClasses {
+		for _, g := range c.Groups {
+			if g.ID == groupID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// updatedBetween reports whether the tournament was last updated within
+// the given dates (YYYY-MM-DD, inclusive)
+func (t *tournamentRef) updatedBetween(start, end string) bool {
+	updated := t.LatestUpdated
+	if len(updated) >= len(time.DateOnly) {
+		updated = updated[:len(time.DateOnly)]
+	}
+	return updated >= start && updated <= end
+}
+
+// tournamentSearchAnswer mirrors TournamentSearchAnswerDto
+type tournamentSearchAnswer struct {
+	ID                int    `json:"id"`
+	Name              string `json:"name"`
+	TournamentID      int    `json:"tournamentid"`
+	TournamentName    string `json:"tournamentname"`
+	LatestUpdatedGame string `json:"latestUpdatedGame,omitempty"`
+}
+
+// groups returns a search answer for every group in the tournaments
+// matching the predicate
+func (f *fixtures) groups(match func(t *tournamentRef, groupName string) bool) []tournamentSearchAnswer {
+	result := []tournamentSearchAnswer{}
+	for i := range f.tournaments {
+		t := &f.tournaments[i]
+		for _, c := range t.RootClasses {
+			for _, g := range c.Groups {
+				if match(t, g.Name) {
+					result = append(result, tournamentSearchAnswer{
+						ID:                g.ID,
+						Name:              g.Name,
+						TournamentID:      t.ID,
+						TournamentName:    t.Name,
+						LatestUpdatedGame: t.LatestUpdated,
+					})
+				}
+			}
+		}
+	}
+	return result
+}
+
+// tournamentsMatching returns the tournaments matching the predicate
+func (f *fixtures) tournamentsMatching(match func(t *tournamentRef) bool) []json.RawMessage {
+	result := []json.RawMessage{}
+	for _, t := range f.tournaments {
+		if match(&t) {
+			result = append(result, t.raw)
+		}
+	}
+	return result
+}
+
+func hasPrefixFold(s, prefix string) bool {
+	return strings.HasPrefix(strings.ToLower(s), strings.ToLower(prefix))
+}
@@ -0,0 +1,331 @@
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// routes registers every path in the schack.se API spec
+func (h *Handler) routes() chi.Router {
+	r := chi.NewRouter()
+	f := h.fixtures
+
+	r.Route(BasePath, func(r chi.Router) {
+		// Organisation
+		r.Get("/organisation/federation", f.serveFile("organisation/federation"))
+		r.Get("/organisation/districts", f.serveFile("organisation/districts"))
+		r.Get("/organisation/district/clubs/{districtid}", f.getDistrictClubs)
+		r.Get("/organisation/club/{clubid}", f.getClub)
+		r.Get("/organisation/club/exists/{name}/{id}", f.getClubExists)
+
+		// Player
+		r.Get("/player/{id}/date/{date}", f.getPlayer)
+		r.Get("/player/fideid/{id}/date/{date}", f.getPlayerByFideID)
+		r.Get("/player/fornamn/{fornamn}/efternamn/{efternamn}", f.searchPlayersByName)
+
+		// Rating lists
+		r.Get("/ratinglist/federation/date/{ratingdate}/ratingtype/{ratingtype}/category/{category}", f.getRatingList)
+		r.Get("/ratinglist/district/{id}/date/{ratingdate}/ratingtype/{ratingtype}/category/{category}", f.getRatingList)
+		r.Get("/ratinglist/club/{id}/date/{ratingdate}/ratingtype/{ratingtype}/category/{category}", f.getRatingList)
+
+		// Tournament
+		r.Get("/tournament/tournament/id/{id}", f.getTournament)
+		r.Get("/tournament/group/id/{id}", f.getTournament)
+		r.Get("/tournament/class/id/{id}", f.getTournament)
+		r.Get("/tournament/tournament/updated/{startdate}/{enddate}", f.getUpdatedTournaments)
+		r.Get("/tournament/tournament/updated/{startdate}/{enddate}/{districtid}", f.getUpdatedTournaments)
+		r.Get("/tournament/group/updated/{startdate}/{enddate}", f.getUpdatedGroups)
+		r.Get("/tournament/group/updated/{startdate}/{enddate}/{districtid}", f.getUpdatedGroups)
+		r.Get("/tournament/group/search/{searchWord}", f.searchGroups)
+		r.Get("/tournament/group/coming", f.getComingTournaments)
+		r.Get("/tournament/group/coming/{districtid}", f.getComingTournaments)
+
+		// Tournament results
+		r.Get("/tournamentresults/table/id/{id}", f.serveResults("tournamentresults/table"))
+		r.Get("/tournamentresults/table/memberid/{id}", f.serveResults("tournamentresults/memberid"))
+		r.Get("/tournamentresults/roundresults/id/{id}", f.serveResults("tournamentresults/roundresults"))
+		r.Get("/tournamentresults/team/table/id/{id}", f.serveResults("tournamentresults/team/table"))
+		r.Get("/tournamentresults/team/roundresults/id/{id}", f.serveResults("tournamentresults/team/roundresults"))
+		r.Get("/tournamentresults/team/roundresults/id/{id}/memberid/{memberid}", f.getTeamRoundResultsForMember)
+		r.Get("/tournamentresults/game/memberid/{id}", f.serveResults("tournamentresults/game"))
+
+		// Team registration
+		r.Get("/tournamentteamregistration/tournament/{id}/club/{clubid}", f.getTeamRegistration)
+	})
+
+	return r
+}
+
+// serveFile serves a fixture document as is
+func (f *fixtures) serveFile(key string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		data, ok := f.file(key)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		writeRaw(w, data)
+	}
+}
+
+// serveResults serves the results fixture for the {id} parameter. IDs
+// without a fixture have no results.
+func (f *fixtures) serveResults(dir string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, ok := intParam(w, r, "id")
+		if !ok {
+			return
+		}
+		data, found := f.file(fmt.Sprintf("%s/%d", dir, id))
+		if !found {
+			data = []byte("[]")
+		}
+		writeRaw(w, data)
+	}
+}
+
+func (f *fixtures) getDistrictClubs(w http.ResponseWriter, r *http.Request) {
+	districtID, ok := intParam(w, r, "districtid")
+	if !ok {
+		return
+	}
+	writeJSON(w, f.districtClubs(districtID))
+}
+
+func (f *fixtures) getClub(w http.ResponseWriter, r *http.Request) {
+	clubID, ok := intParam(w, r, "clubid")
+	if !ok {
+		return
+	}
+	club, found := f.club(clubID)
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+	writeRaw(w, club.raw)
+}
+
+// getClubExists reports whether another club than {id} already has the name
+func (f *fixtures) getClubExists(w http.ResponseWriter, r *http.Request) {
+	clubID, ok := intParam(w, r, "id")
+	if !ok {
+		return
+	}
+	name := pathParam(r, "name")
+
+	exists := false
+	for _, c := range f.clubs {
+		if c.ID != clubID && strings.EqualFold(c.Name, name) {
+			exists = true
+			break
+		}
+	}
+	writeJSON(w, exists)
+}
+
+func (f *fixtures) getPlayer(w http.ResponseWriter, r *http.Request) {
+	memberID, ok := intParam(w, r, "id")
+	if !ok {
+		return
+	}
+	f.writePlayer(w, r, func(p *playerRef) bool { return p.ID == memberID })
+}
+
+func (f *fixtures) getPlayerByFideID(w http.ResponseWriter, r *http.Request) {
+	fideID, ok := intParam(w, r, "id")
+	if !ok {
+		return
+	}
+	f.writePlayer(w, r, func(p *playerRef) bool { return p.FideID != 0 && p.FideID == fideID })
+}
+
+// writePlayer writes the matching player. Fixtures hold a single rating per
+// player, so the date is ignored.
+func (f *fixtures) writePlayer(w http.ResponseWriter, r *http.Request, match func(p *playerRef) bool) {
+	player, found := f.player(match)
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+	writeRaw(w, player)
+}
+
+func (f *fixtures) searchPlayersByName(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, f.searchPlayers(pathParam(r, "fornamn"), pathParam(r, "efternamn")))
+}
+
+// getRatingList serves the federation, district or club rating list,
+// depending on the route. The date and category are ignored.
+func (f *fixtures) getRatingList(w http.ResponseWriter, r *http.Request) {
+	ratingType, ok := intParam(w, r, "ratingtype")
+	if !ok {
+		return
+	}
+	if _, ok := intParam(w, r, "category"); !ok {
+		return
+	}
+
+	match := func(p *playerRef) bool { return true }
+	if chi.URLParam(r, "id") != "" {
+		id, ok := intParam(w, r, "id")
+		if !ok {
+			return
+		}
+		if strings.Contains(r.URL.Path, "/ratinglist/district/") {
+			match = func(p *playerRef) bool {
+				club, found := f.club(p.ClubID)
+				return found && club.inDistrict(id)
+			}
+		} else {
+			match = func(p *playerRef) bool { return p.ClubID == id }
+		}
+	}
+
+	writeJSON(w, f.ratingList(ratingType, match))
+}
+
+// getTournament serves a tournament by tournament, group or class ID,
+// depending on the route
+func (f *fixtures) getTournament(w http.ResponseWriter, r *http.Request) {
+	id, ok := intParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	match := func(t *tournamentRef) bool { return t.ID == id }
+	switch {
+	case strings.Contains(r.URL.Path, "/tournament/group/"):
+		match = func(t *tournamentRef) bool { return t.hasGroup(id) }
+	case strings.Contains(r.URL.Path, "/tournament/class/"):
+		match = func(t *tournamentRef) bool { return t.hasClass(id) }
+	}
+
+	tournament, found := f.tournament(match)
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+	writeRaw(w, tournament)
+}
+
+// getUpdatedTournaments serves tournaments updated within the date range.
+// Fixtures carry no district, so the district filter is ignored.
+func (f *fixtures) getUpdatedTournaments(w http.ResponseWriter, r *http.Request) {
+	start, end := chi.URLParam(r, "startdate"), chi.URLParam(r, "enddate")
+	writeJSON(w, f.tournamentsMatching(func(t *tournamentRef) bool { return t.updatedBetween(start, end) }))
+}
+
+// getUpdatedGroups serves the groups of tournaments updated within the date range
+func (f *fixtures) getUpdatedGroups(w http.ResponseWriter, r *http.Request) {
+	start, end := chi.URLParam(r, "startdate"), chi.URLParam(r, "enddate")
+	writeJSON(w, f.groups(func(t *tournamentRef, _ string) bool { return t.updatedBetween(start, end) }))
+}
+
+// searchGroups serves groups whose name or tournament name contains the search word
+func (f *fixtures) searchGroups(w http.ResponseWriter, r *http.Request) {
+	word := strings.ToLower(pathParam(r, "searchWord"))
+	writeJSON(w, f.groups(func(t *tournamentRef, groupName string) bool {
+		return strings.Contains(strings.ToLower(t.Name), word) || strings.Contains(strings.ToLower(groupName), word)
+	}))
+}
+
+// getComingTournaments serves every tournament. Fixture dates are fixed, so
+// filtering on the current date would make the result change over time.
+func (f *fixtures) getComingTournaments(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, f.tournamentsMatching(func(t *tournamentRef) bool { return true }))
+}
+
+// getTeamRoundResultsForMember serves the team round results with a game
+// played by the member
+func (f *fixtures) getTeamRoundResultsForMember(w http.ResponseWriter, r *http.Request) {
+	groupID, ok := intParam(w, r, "id")
+	if !ok {
+		return
+	}
+	memberID, ok := intParam(w, r, "memberid")
+	if !ok {
+		return
+	}
+
+	var rounds []struct {
+		Games []struct {
+			WhiteID int `json:"whiteId"`
+			BlackID int `json:"blackId"`
+		} `json:"games"`
+	}
+	var raw []json.RawMessage
+	if data, found := f.file(fmt.Sprintf("tournamentresults/team/roundresults/%d", groupID)); found {
+		if err := json.Unmarshal(data, &raw); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := json.Unmarshal(data, &rounds); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	result := []json.RawMessage{}
+	for i, round := range rounds {
+		for _, g := range round.Games {
+			if g.WhiteID == memberID || g.BlackID == memberID {
+				result = append(result, raw[i])
+				break
+			}
+		}
+	}
+	writeJSON(w, result)
+}
+
+func (f *fixtures) getTeamRegistration(w http.ResponseWriter, r *http.Request) {
+	tournamentID, ok := intParam(w, r, "id")
+	if !ok {
+		return
+	}
+	clubID, ok := intParam(w, r, "clubid")
+	if !ok {
+		return
+	}
+	f.serveFile(fmt.Sprintf("tournamentteamregistration/%d-%d", tournamentID, clubID))(w, r)
+}
+
+// intParam parses an integer URL parameter, writing a 400 response if it is invalid
+func intParam(w http.ResponseWriter, r *http.Request, name string) (int, bool) {
+	value, err := strconv.Atoi(chi.URLParam(r, name))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid %s", name), http.StatusBadRequest)
+		return 0, false
+	}
+	return value, true
+}
+
+// pathParam returns a string URL parameter. chi matches on the escaped
+// path when there is one, so the parameter may still be escaped.
+func pathParam(r *http.Request, name string) string {
+	value := chi.URLParam(r, name)
+	if unescaped, err := url.PathUnescape(value); err == nil {
+		return unescaped
+	}
+	return value
+}
+
+func writeRaw(w http.ResponseWriter, data []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeRaw(w, data)
+}
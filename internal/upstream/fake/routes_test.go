@@ -0,0 +1,38 @@
+package fake
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// TestRoutes_CoverSpec checks that every path in the API spec is routed
+func TestRoutes_CoverSpec(t *testing.T) {
+	data, err := os.ReadFile("../../../api-specs/schack-se-api-docs.json")
+	if err != nil {
+		t.Fatalf("Read spec: %v", err)
+	}
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatalf("Decode spec: %v", err)
+	}
+
+	router := NewHandler().router
+	param := regexp.MustCompile(`\{[^}]+\}`)
+	for path, methods := range spec.Paths {
+		if _, ok := methods["get"]; !ok {
+			continue
+		}
+		// chi parameters match any segment, so any value will do
+		example := param.ReplaceAllString(path, "1")
+		if !router.Match(chi.NewRouteContext(), http.MethodGet, example) {
+			t.Errorf("No route for GET %s", path)
+		}
+	}
+}
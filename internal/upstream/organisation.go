@@ -16,6 +16,12 @@ func (c *Client) GetFederation(ctx context.Context) (*model.Federation, error) {
 	return &federation, nil
 }
 
+// GetFederationRaw fetches Swedish Chess Federation info as the raw upstream
+// JSON
+func (c *Client) GetFederationRaw(ctx context.Context) ([]byte, error) {
+	return c.GetRaw(ctx, "/organisation/federation")
+}
+
 // GetDistricts fetches all districts
 func (c *Client) GetDistricts(ctx context.Context) ([]model.District, error) {
 	var districts []model.District
@@ -25,6 +31,11 @@ func (c *Client) GetDistricts(ctx context.Context) ([]model.District, error) {
 	return districts, nil
 }
 
+// GetDistrictsRaw fetches all districts as the raw upstream JSON
+func (c *Client) GetDistrictsRaw(ctx context.Context) ([]byte, error) {
+	return c.GetRaw(ctx, "/organisation/districts")
+}
+
 // GetClubsInDistrict fetches clubs in a district
 func (c *Client) GetClubsInDistrict(ctx context.Context, districtID int) ([]model.Club, error) {
 	path := fmt.Sprintf("/organisation/district/clubs/%d", districtID)
@@ -35,6 +46,11 @@ func (c *Client) GetClubsInDistrict(ctx context.Context, districtID int) ([]mode
 	return clubs, nil
 }
 
+// GetClubsInDistrictRaw fetches clubs in a district as the raw upstream JSON
+func (c *Client) GetClubsInDistrictRaw(ctx context.Context, districtID int) ([]byte, error) {
+	return c.GetRaw(ctx, fmt.Sprintf("/organisation/district/clubs/%d", districtID))
+}
+
 // GetClub fetches a specific club
 func (c *Client) GetClub(ctx context.Context, clubID int) (*model.Club, error) {
 	path := fmt.Sprintf("/organisation/club/%d", clubID)
@@ -45,6 +61,11 @@ func (c *Client) GetClub(ctx context.Context, clubID int) (*model.Club, error) {
 	return &club, nil
 }
 
+// GetClubRaw fetches a specific club as the raw upstream JSON
+func (c *Client) GetClubRaw(ctx context.Context, clubID int) ([]byte, error) {
+	return c.GetRaw(ctx, fmt.Sprintf("/organisation/club/%d", clubID))
+}
+
 // ClubNameExists checks if a club name exists (other than for the given club ID)
 func (c *Client) ClubNameExists(ctx context.Context, name string, clubID int) (bool, error) {
 	path := fmt.Sprintf("/organisation/club/exists/%s/%d", name, clubID)
@@ -17,6 +17,11 @@ func (c *Client) GetFederationRatingList(ctx context.Context, date string, ratin
 	return players, nil
 }
 
+// GetFederationRatingListRaw fetches federation-wide rating list as the raw upstream JSON
+func (c *Client) GetFederationRatingListRaw(ctx context.Context, date string, ratingType, category int) ([]byte, error) {
+	return c.GetRaw(ctx, fmt.Sprintf("/ratinglist/federation/date/%s/ratingtype/%d/category/%d", date, ratingType, category))
+}
+
 // GetDistrictRatingList fetches district rating list
 func (c *Client) GetDistrictRatingList(ctx context.Context, districtID int, date string, ratingType, category int) ([]model.PlayerInfo, error) {
 	path := fmt.Sprintf("/ratinglist/district/%d/date/%s/ratingtype/%d/category/%d", districtID, date, ratingType, category)
@@ -27,6 +32,11 @@ func (c *Client) GetDistrictRatingList(ctx context.Context, districtID int, date
 	return players, nil
 }
 
+// GetDistrictRatingListRaw fetches district rating list as the raw upstream JSON
+func (c *Client) GetDistrictRatingListRaw(ctx context.Context, districtID int, date string, ratingType, category int) ([]byte, error) {
+	return c.GetRaw(ctx, fmt.Sprintf("/ratinglist/district/%d/date/%s/ratingtype/%d/category/%d", districtID, date, ratingType, category))
+}
+
 // GetClubRatingList fetches club rating list
 func (c *Client) GetClubRatingList(ctx context.Context, clubID int, date string, ratingType, category int) ([]model.PlayerInfo, error) {
 	path := fmt.Sprintf("/ratinglist/club/%d/date/%s/ratingtype/%d/category/%d", clubID, date, ratingType, category)
@@ -36,3 +46,8 @@ func (c *Client) GetClubRatingList(ctx context.Context, clubID int, date string,
 	}
 	return players, nil
 }
+
+// GetClubRatingListRaw fetches club rating list as the raw upstream JSON
+func (c *Client) GetClubRatingListRaw(ctx context.Context, clubID int, date string, ratingType, category int) ([]byte, error) {
+	return c.GetRaw(ctx, fmt.Sprintf("/ratinglist/club/%d/date/%s/ratingtype/%d/category/%d", clubID, date, ratingType, category))
+}
@@ -17,6 +17,12 @@ func (c *Client) GetResultTable(ctx context.Context, groupID int) ([]model.Tourn
 	return results, nil
 }
 
+// GetResultTableRaw fetches individual tournament table by group ID as the
+// raw upstream JSON
+func (c *Client) GetResultTableRaw(ctx context.Context, groupID int) ([]byte, error) {
+	return c.GetRaw(ctx, fmt.Sprintf("/tournamentresults/table/id/%d", groupID))
+}
+
 // GetMemberTableResults fetches member's tournament results
 func (c *Client) GetMemberTableResults(ctx context.Context, memberID int) ([]model.TournamentEndResult, error) {
 	path := fmt.Sprintf("/tournamentresults/table/memberid/%d", memberID)
@@ -37,6 +43,12 @@ func (c *Client) GetRoundResults(ctx context.Context, groupID int) ([]model.Tour
 	return results, nil
 }
 
+// GetRoundResultsRaw fetches round results for a group as the raw upstream
+// JSON
+func (c *Client) GetRoundResultsRaw(ctx context.Context, groupID int) ([]byte, error) {
+	return c.GetRaw(ctx, fmt.Sprintf("/tournamentresults/roundresults/id/%d", groupID))
+}
+
 // GetTeamResultTable fetches team tournament table by group ID
 func (c *Client) GetTeamResultTable(ctx context.Context, groupID int) ([]model.TeamTournamentEndResult, error) {
 	path := fmt.Sprintf("/tournamentresults/team/table/id/%d", groupID)
@@ -47,6 +59,12 @@ func (c *Client) GetTeamResultTable(ctx context.Context, groupID int) ([]model.T
 	return results, nil
 }
 
+// GetTeamResultTableRaw fetches team tournament table by group ID as the raw
+// upstream JSON
+func (c *Client) GetTeamResultTableRaw(ctx context.Context, groupID int) ([]byte, error) {
+	return c.GetRaw(ctx, fmt.Sprintf("/tournamentresults/team/table/id/%d", groupID))
+}
+
 // GetTeamRoundResults fetches team round results for a group
 func (c *Client) GetTeamRoundResults(ctx context.Context, groupID int) ([]model.TournamentRoundResult, error) {
 	path := fmt.Sprintf("/tournamentresults/team/roundresults/id/%d", groupID)
@@ -57,6 +75,12 @@ func (c *Client) GetTeamRoundResults(ctx context.Context, groupID int) ([]model.
 	return results, nil
 }
 
+// GetTeamRoundResultsRaw fetches team round results for a group as the raw
+// upstream JSON
+func (c *Client) GetTeamRoundResultsRaw(ctx context.Context, groupID int) ([]byte, error) {
+	return c.GetRaw(ctx, fmt.Sprintf("/tournamentresults/team/roundresults/id/%d", groupID))
+}
+
 // GetTeamRoundResultsForMember fetches team round results for a specific member
 func (c *Client) GetTeamRoundResultsForMember(ctx context.Context, groupID, memberID int) ([]model.TournamentRoundResult, error) {
 	path := fmt.Sprintf("/tournamentresults/team/roundresults/id/%d/memberid/%d", groupID, memberID)
@@ -0,0 +1,87 @@
+package upstream
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed upstream requests are retried.
+// Transport errors, 5xx and 429 responses are retried with jittered
+// exponential backoff. The zero value disables retries.
+type RetryPolicy struct {
+	MaxRetries int           // Retries after the first attempt (0 = no retries)
+	BaseDelay  time.Duration // Backoff before the first retry, doubled for each retry
+	MaxDelay   time.Duration // Upper bound for backoff and Retry-After waits
+	Budget     time.Duration // Overall time for a request, attempts and waits included (0 = callers' deadlines only)
+}
+
+// next returns how long to wait before retrying after a failed attempt
+// (0-based), or false if the request should not be retried
+func (p RetryPolicy) next(ctx context.Context, attempt int, err error) (time.Duration, bool) {
+	if attempt >= p.MaxRetries || ctx.Err() != nil {
+		return 0, false
+	}
+
+	// Only retry outages, and not once the circuit breaker has given up on
+	// upstream or when replaying a request that was never recorded
+	if !errors.Is(err, ErrUnavailable) || errors.Is(err, ErrCircuitOpen) || errors.Is(err, ErrNoCassette) {
+		return 0, false
+	}
+
+	delay := p.backoff(attempt)
+	var upstreamErr *Error
+	if errors.As(err, &upstreamErr) && upstreamErr.RetryAfter > 0 {
+		// Upstream told us when to come back. Give up rather than wait
+		// longer than we are willing to.
+		if p.MaxDelay > 0 && upstreamErr.RetryAfter > p.MaxDelay {
+			return 0, false
+		}
+		delay = max(delay, upstreamErr.RetryAfter)
+	}
+
+	// Don't start a wait that will outlive the request. For shared requests
+	// the deadline is the latest of the waiting callers' deadlines.
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		return 0, false
+	}
+
+	return delay, true
+}
+
+// backoff returns the jittered exponential backoff for an attempt.
+// Half the delay is fixed and half is random, so retries from concurrent
+// requests spread out without collapsing to zero.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << attempt
+	if delay <= 0 || (p.MaxDelay > 0 && delay > p.MaxDelay) {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+// parseRetryAfter parses a Retry-After header given either as delay seconds
+// or as an HTTP date. Returns 0 if the header is absent or invalid.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,123 @@
+package upstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{MaxRetries: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration // Before jitter
+	}{
+		{0, 100 * time.Millisecond},
+		{1, 200 * time.Millisecond},
+		{2, 400 * time.Millisecond},
+		{3, 800 * time.Millisecond},
+		{4, time.Second}, // Capped at MaxDelay
+		{10, time.Second},
+		{70, time.Second}, // Shift overflow
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("Attempt%d", tt.attempt), func(t *testing.T) {
+			for range 100 {
+				got := p.backoff(tt.attempt)
+				if got < tt.want/2 || got > tt.want {
+					t.Fatalf("backoff(%d) = %v, want within [%v, %v]", tt.attempt, got, tt.want/2, tt.want)
+				}
+			}
+		})
+	}
+
+	t.Run("NoDelays", func(t *testing.T) {
+		if got := (RetryPolicy{MaxRetries: 3}).backoff(1); got != 0 {
+			t.Errorf("backoff(1) = %v, want 0", got)
+		}
+	})
+}
+
+func TestRetryPolicy_Next(t *testing.T) {
+	p := RetryPolicy{MaxRetries: 2, BaseDelay: 10 * time.Millisecond, MaxDelay: time.Second}
+
+	tests := []struct {
+		name     string
+		attempt  int
+		err      error
+		deadline time.Duration // 0 = no deadline
+		wantOK   bool
+		minDelay time.Duration
+	}{
+		{"ServerError", 0, &Error{StatusCode: http.StatusServiceUnavailable}, 0, true, 5 * time.Millisecond},
+		{"TooManyRequests", 1, &Error{StatusCode: http.StatusTooManyRequests}, 0, true, 10 * time.Millisecond},
+		{"TransportError", 0, &transportError{err: errors.New("connection refused")}, 0, true, 5 * time.Millisecond},
+		{"RetriesExhausted", 2, &Error{StatusCode: http.StatusServiceUnavailable}, 0, false, 0},
+		{"NotFound", 0, &Error{StatusCode: http.StatusNotFound}, 0, false, 0},
+		{"BadRequest", 0, &Error{StatusCode: http.StatusBadRequest}, 0, false, 0},
+		{"CircuitOpen", 0, ErrCircuitOpen, 0, false, 0},
+		{"NoCassette", 0, fmt.Errorf("GET /x: %w", ErrNoCassette), 0, false, 0},
+		{"RetryAfter", 0, &Error{StatusCode: http.StatusTooManyRequests, RetryAfter: 500 * time.Millisecond}, 0, true, 500 * time.Millisecond},
+		{"RetryAfterPastMaxDelay", 0, &Error{StatusCode: http.StatusTooManyRequests, RetryAfter: 2 * time.Second}, 0, false, 0},
+		{"RetryAfterPastDeadline", 0, &Error{StatusCode: http.StatusTooManyRequests, RetryAfter: 500 * time.Millisecond}, 100 * time.Millisecond, false, 0},
+		{"BackoffWithinDeadline", 0, &Error{StatusCode: http.StatusServiceUnavailable}, time.Second, true, 5 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.deadline > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.deadline)
+				defer cancel()
+			}
+
+			delay, ok := p.next(ctx, tt.attempt, tt.err)
+			if ok != tt.wantOK {
+				t.Fatalf("next(%d, %v) retry = %v, want %v", tt.attempt, tt.err, ok, tt.wantOK)
+			}
+			if ok && delay < tt.minDelay {
+				t.Errorf("next(%d, %v) delay = %v, want at least %v", tt.attempt, tt.err, delay, tt.minDelay)
+			}
+		})
+	}
+
+	t.Run("Cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, ok := p.next(ctx, 0, &Error{StatusCode: http.StatusServiceUnavailable}); ok {
+			t.Error("Retried after the context was cancelled")
+		}
+	})
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+	}{
+		{"Empty", "", 0},
+		{"Seconds", "120", 2 * time.Minute},
+		{"ZeroSeconds", "0", 0},
+		{"NegativeSeconds", "-5", 0},
+		{"HTTPDate", now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{"PastHTTPDate", now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"Invalid", "soon", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRetryAfter(tt.value, now); got != tt.want {
+				t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -18,6 +18,11 @@ func (c *Client) GetTournament(ctx context.Context, tournamentID int) (*model.To
 	return &tournament, nil
 }
 
+// GetTournamentRaw fetches tournament by ID as the raw upstream JSON
+func (c *Client) GetTournamentRaw(ctx context.Context, tournamentID int) ([]byte, error) {
+	return c.GetRaw(ctx, fmt.Sprintf("/tournament/tournament/id/%d", tournamentID))
+}
+
 // GetTournamentFromGroup fetches tournament info from a group ID
 func (c *Client) GetTournamentFromGroup(ctx context.Context, groupID int) (*model.Tournament, error) {
 	path := fmt.Sprintf("/tournament/group/id/%d", groupID)
@@ -28,6 +33,12 @@ func (c *Client) GetTournamentFromGroup(ctx context.Context, groupID int) (*mode
 	return &tournament, nil
 }
 
+// GetTournamentFromGroupRaw fetches tournament info from a group ID as the
+// raw upstream JSON
+func (c *Client) GetTournamentFromGroupRaw(ctx context.Context, groupID int) ([]byte, error) {
+	return c.GetRaw(ctx, fmt.Sprintf("/tournament/group/id/%d", groupID))
+}
+
 // GetTournamentFromClass fetches tournament info from a class ID
 func (c *Client) GetTournamentFromClass(ctx context.Context, classID int) (*model.Tournament, error) {
 	path := fmt.Sprintf("/tournament/class/id/%d", classID)
@@ -38,6 +49,12 @@ func (c *Client) GetTournamentFromClass(ctx context.Context, classID int) (*mode
 	return &tournament, nil
 }
 
+// GetTournamentFromClassRaw fetches tournament info from a class ID as the
+// raw upstream JSON
+func (c *Client) GetTournamentFromClassRaw(ctx context.Context, classID int) ([]byte, error) {
+	return c.GetRaw(ctx, fmt.Sprintf("/tournament/class/id/%d", classID))
+}
+
 // SearchTournamentGroups searches for tournament groups by name/location
 func (c *Client) SearchTournamentGroups(ctx context.Context, searchWord string) ([]model.TournamentSearchAnswer, error) {
 	path := fmt.Sprintf("/tournament/group/search/%s", url.PathEscape(searchWord))
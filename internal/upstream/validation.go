@@ -0,0 +1,42 @@
+package upstream
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/msvens/mchess/internal/metrics"
+	"github.com/msvens/mchess/internal/spec"
+)
+
+var upstreamInvalid = metrics.NewCounterVec(
+	"mchess_upstream_invalid_responses_total",
+	"Total number of upstream responses rejected because they do not match the API spec.",
+	"endpoint",
+)
+
+// WithValidation checks every successful upstream response against the API
+// spec, so that e.g. an HTML error page sent with status 200 fails with
+// ErrInvalidResponse instead of being forwarded. nil leaves the client
+// unchanged.
+func WithValidation(v *spec.Validator) Option {
+	return func(c *Client) {
+		c.validator = v
+	}
+}
+
+// validate checks a response body against the spec
+func (c *Client) validate(path string, body []byte) error {
+	err := c.validator.Validate(path, body)
+	if err == nil {
+		return nil
+	}
+
+	endpoint := path
+	if validationErr, ok := err.(*spec.ValidationError); ok {
+		endpoint = validationErr.Endpoint
+	}
+	upstreamInvalid.Inc(endpoint)
+	slog.Warn("Invalid upstream response", "path", path, "error", err)
+
+	return fmt.Errorf("validate response: %w: %w", ErrInvalidResponse, err)
+}
@@ -0,0 +1,47 @@
+package upstream_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/msvens/mchess/internal/spec"
+	"github.com/msvens/mchess/internal/upstream"
+)
+
+func TestValidation(t *testing.T) {
+	s, err := spec.Load("")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	// An error page sent with status 200
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><body>Maintenance</body></html>"))
+	}))
+	defer server.Close()
+
+	t.Run("Enabled_RejectsInvalidResponse", func(t *testing.T) {
+		client := upstream.NewClient(server.URL, 5*time.Second, 100, upstream.WithValidation(spec.NewValidator(s)))
+		_, err := client.GetRaw(context.Background(), "/tournament/group/coming")
+
+		var validationErr *spec.ValidationError
+		if !errors.Is(err, upstream.ErrInvalidResponse) || !errors.As(err, &validationErr) {
+			t.Fatalf("Error: got %v, want ErrInvalidResponse with ValidationError", err)
+		}
+		if validationErr.Endpoint != "/tournament/group/coming" {
+			t.Errorf("Endpoint: got %s, want /tournament/group/coming", validationErr.Endpoint)
+		}
+	})
+
+	t.Run("Disabled_ForwardsResponse", func(t *testing.T) {
+		client := upstream.NewClient(server.URL, 5*time.Second, 100, upstream.WithValidation(nil))
+		if _, err := client.GetRaw(context.Background(), "/tournament/group/coming"); err != nil {
+			t.Errorf("GetRaw: got %v, want success", err)
+		}
+	})
+}
@@ -0,0 +1,3 @@
+DELETE FROM cache_stats WHERE cache_type = 'tournament';
+DROP TABLE IF EXISTS tournament_cache;
+DELETE FROM schema_version WHERE version = 2;
@@ -0,0 +1,3 @@
+DELETE FROM cache_stats WHERE cache_type = 'results';
+DROP TABLE IF EXISTS results_cache;
+DELETE FROM schema_version WHERE version = 3;
@@ -0,0 +1,22 @@
+INSERT INTO schema_version (version, description)
+VALUES (3, 'Tournament results cache');
+
+-- Tournament results cache, one row per group and result type
+-- Results are immutable once every round is finalized and the group has ended
+CREATE TABLE results_cache (
+    group_id        INTEGER NOT NULL,
+    result_type     TEXT NOT NULL,              -- 'table', 'roundresults', 'teamtable', 'teamroundresults'
+    -- Full response stored as JSONB for complete data
+    data            JSONB NOT NULL,
+    -- Cache metadata
+    fetched_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at      TIMESTAMPTZ,                -- NULL = never expires (finalized results)
+
+    PRIMARY KEY (group_id, result_type)
+);
+
+CREATE INDEX idx_results_cache_expires
+    ON results_cache(expires_at)
+    WHERE expires_at IS NOT NULL;
+
+INSERT INTO cache_stats (cache_type) VALUES ('results');
@@ -0,0 +1,3 @@
+DELETE FROM cache_stats WHERE cache_type = 'ratinglist';
+DROP TABLE IF EXISTS rating_list_snapshot;
+DELETE FROM schema_version WHERE version = 4;
@@ -0,0 +1,25 @@
+INSERT INTO schema_version (version, description)
+VALUES (4, 'Rating list snapshots');
+
+-- Monthly rating list snapshots
+-- Past months never change, only the current month needs TTL
+CREATE TABLE rating_list_snapshot (
+    scope           TEXT NOT NULL,              -- 'federation', 'district', 'club'
+    scope_id        INTEGER NOT NULL,           -- District/club ID, 0 for federation
+    rating_date     DATE NOT NULL,              -- First of month: 2024-06-01
+    rating_type     INTEGER NOT NULL,
+    category        INTEGER NOT NULL,
+    -- Full response stored as JSONB for complete data
+    data            JSONB NOT NULL,
+    -- Cache metadata
+    fetched_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at      TIMESTAMPTZ,                -- NULL = never expires
+
+    PRIMARY KEY (scope, scope_id, rating_date, rating_type, category)
+);
+
+CREATE INDEX idx_rating_list_snapshot_expires
+    ON rating_list_snapshot(expires_at)
+    WHERE expires_at IS NOT NULL;
+
+INSERT INTO cache_stats (cache_type) VALUES ('ratinglist');
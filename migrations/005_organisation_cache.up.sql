@@ -0,0 +1,22 @@
+INSERT INTO schema_version (version, description)
+VALUES (5, 'Organisation cache');
+
+-- Organisation cache (federation, districts, clubs)
+-- This data changes a few times a year, so entries have a long TTL and
+-- are refreshed in the background before they expire
+CREATE TABLE organisation_cache (
+    kind            TEXT NOT NULL,              -- 'federation', 'districts', 'districtclubs', 'club'
+    id              INTEGER NOT NULL,           -- District/club ID, 0 when not applicable
+    -- Full response stored as JSONB for complete data
+    data            JSONB NOT NULL,
+    -- Cache metadata
+    fetched_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at      TIMESTAMPTZ NOT NULL,
+
+    PRIMARY KEY (kind, id)
+);
+
+CREATE INDEX idx_organisation_cache_expires
+    ON organisation_cache(expires_at);
+
+INSERT INTO cache_stats (cache_type) VALUES ('organisation');
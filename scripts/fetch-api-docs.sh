@@ -100,4 +100,11 @@ echo "Quick stats:"
 echo "  - OpenAPI version: $(jq -r '.openapi // .swagger' "$CURRENT_FILE")"
 echo "  - API version: $NEW_VERSION"
 echo "  - Endpoints: $(jq -r '.paths | keys | length' "$CURRENT_FILE")"
-echo "  - Schemas: $(jq -r '.components.schemas // .definitions | keys | length' "$CURRENT_FILE" 2>/dev/null || echo "N/A")"
\ No newline at end of file
+echo "  - Schemas: $(jq -r '.components.schemas // .definitions | keys | length' "$CURRENT_FILE" 2>/dev/null || echo "N/A")"
+
+# Check that the models still match the spec
+echo ""
+echo "Checking models against the spec..."
+if ! (cd "$PROJECT_ROOT" && go run . spec check); then
+    echo "Update internal/model (and internal/spec if a schema was added) to match the spec"
+fi